@@ -0,0 +1,32 @@
+package colour
+
+import (
+	"io"
+	"sync/atomic"
+)
+
+// bellOnAlert is non-zero when Alert rings the terminal bell.
+var bellOnAlert int32
+
+// SetBellOnAlert sets whether Alert rings the terminal bell after printing,
+// so a user who has looked away notices a failure. It is off by default.
+func SetBellOnAlert(enable bool) {
+	var v int32
+	if enable {
+		v = 1
+	}
+	atomic.StoreInt32(&bellOnAlert, v)
+}
+
+// Alert is a convenient helper function to print an error message to Error
+// in the "error" colour of the current theme, red by default, followed by a
+// bell if enabled with SetBellOnAlert. A newline is appended to format by
+// default.
+func Alert(format string, a ...interface{}) {
+	w := GetError()
+	printColour(w, format, Named("error"), a...)
+
+	if atomic.LoadInt32(&bellOnAlert) != 0 {
+		io.WriteString(w, "\a")
+	}
+}
@@ -0,0 +1,38 @@
+package colour
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAlert(t *testing.T) {
+	NoColour = false
+	rb := new(bytes.Buffer)
+	old := Error
+	Error = rb
+	defer func() { Error = old }()
+
+	Alert("failed: %d", 3)
+	if got, want := rb.String(), "\x1b[31mfailed: 3\n\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+
+	SetBellOnAlert(true)
+	defer SetBellOnAlert(false)
+
+	rb.Reset()
+	Alert("failed")
+	if got, want := rb.String(), "\x1b[31mfailed\n\x1b[0m\a"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+
+	SetBellOnAlert(false)
+	SetTheme(Theme{"error": New(FgMagenta, Bold)})
+	defer SetTheme(DefaultTheme)
+
+	rb.Reset()
+	Alert("failed")
+	if got, want := rb.String(), "\x1b[35;1mfailed\n\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+}
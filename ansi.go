@@ -0,0 +1,91 @@
+package colour
+
+import "strings"
+
+// resetSequence turns off all attributes.
+const resetSequence = escape + "[0m"
+
+// escapeLen returns the length in bytes of the escape sequence at the start of
+// s, which must begin with ESC. It returns -1 when s ends before the sequence
+// is complete, so streaming callers know to wait for more input.
+//
+// CSI sequences (ESC [ ... final) and string sequences such as OSC
+// (ESC ] ... BEL or ESC \) are recognised, anything else is treated as a two
+// byte escape.
+func escapeLen(s string) int {
+	if len(s) < 2 {
+		return -1
+	}
+
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			b := s[i]
+			switch {
+			case b >= 0x40 && b <= 0x7e:
+				return i + 1
+			case b < 0x20:
+				// malformed, stop before the control byte
+				return i
+			}
+		}
+		return -1
+
+	case ']', 'P', '_', '^', 'X':
+		for i := 2; i < len(s); i++ {
+			switch s[i] {
+			case '\a':
+				return i + 1
+			case escape[0]:
+				if i+1 == len(s) {
+					return -1
+				}
+				if s[i+1] == '\\' {
+					return i + 2
+				}
+			}
+		}
+		return -1
+	}
+
+	return 2
+}
+
+// Strip returns s with all ANSI escape sequences removed, such as SGR
+// colours, other CSI sequences and OSC sequences like hyperlinks. Everything
+// else, including multi-byte UTF-8, is left untouched.
+func Strip(s string) string {
+	return stripEscapes(s)
+}
+
+// HasEscape reports whether s contains an ANSI escape sequence, that is
+// whether Strip would change it. It is much cheaper than stripping, so it can
+// decide whether stripping is needed at all. Like Strip it treats any ESC
+// byte as the start of a sequence, even an incomplete one.
+func HasEscape(s string) bool {
+	return strings.IndexByte(s, escape[0]) >= 0
+}
+
+// stripEscapes removes all escape sequences from s. An incomplete sequence at
+// the end of s is removed as well.
+func stripEscapes(s string) string {
+	i := strings.IndexByte(s, escape[0])
+	if i < 0 {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for i >= 0 {
+		b.WriteString(s[:i])
+		l := escapeLen(s[i:])
+		if l < 0 {
+			l = len(s) - i
+		}
+		s = s[i+l:]
+		i = strings.IndexByte(s, escape[0])
+	}
+	b.WriteString(s)
+
+	return b.String()
+}
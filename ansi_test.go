@@ -0,0 +1,48 @@
+package colour
+
+import "testing"
+
+func TestStrip(t *testing.T) {
+	tests := []struct {
+		s, want string
+	}{
+		{"", ""},
+		{"plain", "plain"},
+		{"\x1b[31mred\x1b[0m", "red"},
+		{"\x1b[1;4;31mbold\x1b[0m \x1b[mnext", "bold next"},
+		{"\x1b[38;5;196m256\x1b[0m", "256"},
+		{"\x1b[38;2;255;128;0mtrue\x1b[48;2;0;0;0m\x1b[0mcolour", "truecolour"},
+		{"\x1b[31m\x1b[1m\x1b[4mnested\x1b[0m\x1b[0m", "nested"},
+		{"\x1b[32mgrüße, 世界 😀\x1b[0m", "grüße, 世界 😀"},
+		{"\x1b[2J\x1b[Hcleared", "cleared"},
+		{"\x1b]8;;http://example.com\x1b\\link\x1b]8;;\x1b\\", "link"},
+		{"\x1b]0;title\atext", "text"},
+		{"\x1b[4:3mcurly\x1b[0m", "curly"},
+		{"trailing\x1b[3", "trailing"},
+		{"\x1b7saved\x1b8", "saved"},
+	}
+
+	for i, tt := range tests {
+		if got := Strip(tt.s); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+	}
+}
+
+func TestHasEscape(t *testing.T) {
+	tests := []string{
+		"",
+		"plain text",
+		"grüße",
+		"\x1b[31mred\x1b[0m",
+		"link \x1b]8;;https://example.com\x1b\\x\x1b]8;;\x1b\\",
+		"incomplete \x1b[",
+		"\x1b",
+	}
+
+	for i, s := range tests {
+		if got, want := HasEscape(s), Strip(s) != s; got != want {
+			t.Errorf("[%d] want: %v, got: %v", i, want, got)
+		}
+	}
+}
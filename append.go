@@ -0,0 +1,88 @@
+package colour
+
+// affixCache holds the sequences of a colour, as returned by enabledAffixes,
+// along with what they were built from.
+type affixCache struct {
+	gen            uint64 // the generation of the colour
+	settings       affixSettings
+	prefix, suffix string
+}
+
+// affixSettings holds the package settings the sequences of a colour depend
+// on.
+type affixSettings struct {
+	level             Level
+	nestedReset       bool
+	resetBefore       bool
+	noBlink           bool
+	extendedUnderline bool
+}
+
+// currentAffixSettings returns the current value of the settings in
+// affixSettings.
+func currentAffixSettings() affixSettings {
+	return affixSettings{
+		level:             GetLevel(),
+		nestedReset:       NestedReset,
+		resetBefore:       ResetBefore,
+		noBlink:           NoBlink,
+		extendedUnderline: GetExtendedUnderline(),
+	}
+}
+
+// AppendWrap appends s to dst wrapped in the sequences turning the colour on
+// and off, as printed by Sprint, and returns the extended buffer. Reusing
+// the buffer across calls, for example per log line, avoids the allocations
+// of building a string. The sequences are built on first use and kept until
+// the colour is changed or one of the settings they depend on changes: the
+// colour level, NestedReset, ResetBefore, NoBlink or SetExtendedUnderline.
+// Appending does not allocate otherwise, unless dst has to grow. If colour is
+// disabled only s is appended.
+func (c *Colour) AppendWrap(dst []byte, s string) []byte {
+	s = sanitize(s)
+	if c.isNoColourSet() {
+		return append(dst, s...)
+	}
+
+	prefix, suffix := c.cachedAffixes()
+	dst = append(dst, prefix...)
+	dst = append(dst, s...)
+	return append(dst, suffix...)
+}
+
+// AppendFormat appends the sequence turning the colour on to dst and
+// returns the extended buffer, like AppendWrap without the text and the
+// reset. Nothing is appended if colour is disabled or c has no attributes.
+func (c *Colour) AppendFormat(dst []byte) []byte {
+	if c.isNoColourSet() {
+		return dst
+	}
+
+	prefix, _ := c.cachedAffixes()
+	return append(dst, prefix...)
+}
+
+// cachedAffixes is like enabledAffixes but reuses the sequences built for
+// the current state of c and the package settings.
+func (c *Colour) cachedAffixes() (prefix, suffix string) {
+	settings := currentAffixSettings()
+
+	c.mu.RLock()
+	gen, cache := c.gen, c.cache
+	c.mu.RUnlock()
+
+	if cache != nil && cache.gen == gen && cache.settings == settings {
+		return cache.prefix, cache.suffix
+	}
+
+	prefix, suffix = c.enabledAffixes()
+
+	// the sequences are only kept if c did not change while building them
+	c.mu.Lock()
+	if c.gen == gen {
+		c.cache = &affixCache{gen, settings, prefix, suffix}
+	}
+	c.mu.Unlock()
+
+	return prefix, suffix
+}
@@ -0,0 +1,117 @@
+package colour
+
+import "testing"
+
+func TestAppendWrap(t *testing.T) {
+	NoColour = false
+	defer SetLevel(GetLevel())
+	SetLevel(Level16)
+
+	tests := []struct {
+		c    *Colour
+		want string
+	}{
+		{New(FgRed, Bold), "> \x1b[31;1mfoo\x1b[0m"},
+		{New(), "> foo"},
+		{NewIf(false, FgRed), "> foo"},
+	}
+
+	for i, tt := range tests {
+		if got := string(tt.c.AppendWrap([]byte("> "), "foo")); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+		if got, want := string(tt.c.AppendWrap(nil, "foo")), tt.c.Sprint("foo"); got != want {
+			t.Errorf("[%d] want: %q, got: %q", i, want, got)
+		}
+	}
+}
+
+func TestAppendWrapChanged(t *testing.T) {
+	NoColour = false
+	defer SetLevel(GetLevel())
+	SetLevel(Level256)
+
+	c := NewFg256(196)
+	if got, want := string(c.AppendWrap(nil, "a")), "\x1b[38;5;196ma\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+
+	c.Add(Bold)
+	if got, want := string(c.AppendWrap(nil, "a")), "\x1b[38;5;196;1ma\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+
+	SetLevel(Level16)
+	if got, want := string(c.AppendWrap(nil, "a")), "\x1b[91;1ma\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+}
+
+func TestAppendFormat(t *testing.T) {
+	NoColour = false
+	defer SetLevel(GetLevel())
+	SetLevel(Level16)
+
+	if got, want := string(New(FgRed).AppendFormat([]byte("> "))), "> \x1b[31m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+	if got, want := string(NewIf(false, FgRed).AppendFormat([]byte("> "))), "> "; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+}
+
+func TestAppendWrapAllocs(t *testing.T) {
+	NoColour = false
+	c := New(FgRed, Bold)
+	buf := make([]byte, 0, 64)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = c.AppendWrap(buf[:0], "benchmark")
+	})
+	if allocs != 0 {
+		t.Errorf("want: 0 allocations, got: %v", allocs)
+	}
+}
+
+func BenchmarkAppendWrap(b *testing.B) {
+	NoColour = false
+	c := New(FgRed, Bold)
+	buf := make([]byte, 0, 64)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = c.AppendWrap(buf[:0], "benchmark")
+	}
+}
+
+func TestAppendWrapSettings(t *testing.T) {
+	NoColour = false
+	defer SetLevel(GetLevel())
+	SetLevel(Level16)
+	defer SetExtendedUnderline(GetExtendedUnderline())
+	SetExtendedUnderline(true)
+	defer func() { NoBlink, NestedReset, ResetBefore = false, false, false }()
+
+	c := New(BlinkSlow, CurlyUnderline)
+	tests := []struct {
+		change func()
+		want   string
+	}{
+		{func() {}, "\x1b[5;4:3mx\x1b[0m"},
+		{func() { NoBlink = true }, "\x1b[7;4:3mx\x1b[0m"},
+		{func() { SetExtendedUnderline(false) }, "\x1b[7;4mx\x1b[0m"},
+		{func() { NestedReset = true }, "\x1b[7;4mx\x1b[27;24m"},
+		{func() { ResetBefore = true }, "\x1b[0m\x1b[7;4mx\x1b[27;24m"},
+		{func() { NoBlink, NestedReset, ResetBefore = false, false, false }, "\x1b[5;4mx\x1b[0m"},
+	}
+
+	for i, tt := range tests {
+		tt.change()
+		if got := string(c.AppendWrap(nil, "x")); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+		if got, want := string(c.AppendWrap(nil, "x")), c.Sprint("x"); got != want {
+			t.Errorf("[%d] want: %q, got: %q", i, want, got)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package colour
+
+import "strconv"
+
+var attributeNames = map[Attribute]string{
+	Reset:        "Reset",
+	Bold:         "Bold",
+	Faint:        "Faint",
+	Italic:       "Italic",
+	Underline:    "Underline",
+	BlinkSlow:    "BlinkSlow",
+	BlinkRapid:   "BlinkRapid",
+	ReverseVideo: "ReverseVideo",
+	Concealed:    "Concealed",
+	CrossedOut:   "CrossedOut",
+	Overline:     "Overline",
+
+	Framed:    "Framed",
+	Encircled: "Encircled",
+
+	PrimaryFont: "PrimaryFont",
+	Font1:       "Font1",
+	Font2:       "Font2",
+	Font3:       "Font3",
+	Font4:       "Font4",
+	Font5:       "Font5",
+	Font6:       "Font6",
+	Font7:       "Font7",
+	Font8:       "Font8",
+	Font9:       "Font9",
+
+	DoubleUnderline: "DoubleUnderline",
+	CurlyUnderline:  "CurlyUnderline",
+	DottedUnderline: "DottedUnderline",
+	DashedUnderline: "DashedUnderline",
+
+	DefaultUnderlineColour: "DefaultUnderlineColour",
+
+	FgBlack:   "FgBlack",
+	FgRed:     "FgRed",
+	FgGreen:   "FgGreen",
+	FgYellow:  "FgYellow",
+	FgBlue:    "FgBlue",
+	FgMagenta: "FgMagenta",
+	FgCyan:    "FgCyan",
+	FgWhite:   "FgWhite",
+
+	FgHiBlack:   "FgHiBlack",
+	FgHiRed:     "FgHiRed",
+	FgHiGreen:   "FgHiGreen",
+	FgHiYellow:  "FgHiYellow",
+	FgHiBlue:    "FgHiBlue",
+	FgHiMagenta: "FgHiMagenta",
+	FgHiCyan:    "FgHiCyan",
+	FgHiWhite:   "FgHiWhite",
+
+	BgBlack:   "BgBlack",
+	BgRed:     "BgRed",
+	BgGreen:   "BgGreen",
+	BgYellow:  "BgYellow",
+	BgBlue:    "BgBlue",
+	BgMagenta: "BgMagenta",
+	BgCyan:    "BgCyan",
+	BgWhite:   "BgWhite",
+
+	BgHiBlack:   "BgHiBlack",
+	BgHiRed:     "BgHiRed",
+	BgHiGreen:   "BgHiGreen",
+	BgHiYellow:  "BgHiYellow",
+	BgHiBlue:    "BgHiBlue",
+	BgHiMagenta: "BgHiMagenta",
+	BgHiCyan:    "BgHiCyan",
+	BgHiWhite:   "BgHiWhite",
+}
+
+// String returns the name of the attribute's constant, such as "FgRed", or
+// "Attribute(n)" for values without one.
+func (a Attribute) String() string {
+	if name, ok := attributeNames[a]; ok {
+		return name
+	}
+
+	return "Attribute(" + strconv.Itoa(int(a)) + ")"
+}
@@ -0,0 +1,60 @@
+package colour
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAttributeString(t *testing.T) {
+	tests := []struct {
+		a    Attribute
+		want string
+	}{
+		{Reset, "Reset"},
+		{CrossedOut, "CrossedOut"},
+		{FgRed, "FgRed"},
+		{FgHiWhite, "FgHiWhite"},
+		{BgBlack, "BgBlack"},
+		{BgHiMagenta, "BgHiMagenta"},
+		{Overline, "Overline"},
+		{Framed, "Framed"},
+		{Encircled, "Encircled"},
+		{PrimaryFont, "PrimaryFont"},
+		{Font9, "Font9"},
+		{CurlyUnderline, "CurlyUnderline"},
+		{Attribute(200), "Attribute(200)"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.a.String(); got != tt.want {
+			t.Errorf("want: %q, got: %q", tt.want, got)
+		}
+	}
+
+	if got, want := fmt.Sprint(FgRed), "FgRed"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+	if got, want := fmt.Sprintf("%d", FgRed), "31"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+}
+
+func TestAttributeValues(t *testing.T) {
+	tests := []struct {
+		a    Attribute
+		want int
+	}{
+		{PrimaryFont, 10},
+		{Font1, 11},
+		{Font9, 19},
+		{Framed, 51},
+		{Encircled, 52},
+		{Overline, 53},
+	}
+
+	for _, tt := range tests {
+		if int(tt.a) != tt.want {
+			t.Errorf("%s want: %d, got: %d", tt.a, tt.want, int(tt.a))
+		}
+	}
+}
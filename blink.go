@@ -0,0 +1,23 @@
+package colour
+
+// NoBlink makes BlinkSlow and BlinkRapid render as ReverseVideo, for
+// terminals or accessibility settings where blinking is disabled and the text
+// would otherwise not stand out at all.
+var NoBlink = false
+
+// substitute replaces the parameter group g with a fallback if the terminal
+// does not support it or it is disabled.
+func substitute(g []Attribute) []Attribute {
+	if len(g) != 1 {
+		return g
+	}
+
+	switch a := g[0]; {
+	case isExtendedUnderline(a) && !GetExtendedUnderline():
+		return []Attribute{Underline}
+	case (a == BlinkSlow || a == BlinkRapid) && NoBlink:
+		return []Attribute{ReverseVideo}
+	}
+
+	return g
+}
@@ -0,0 +1,36 @@
+package colour
+
+import "testing"
+
+func TestNoBlink(t *testing.T) {
+	NoColour = false
+	defer SetLevel(GetLevel())
+	SetLevel(Level256)
+	defer func() {
+		NoBlink = false
+		NestedReset = false
+	}()
+
+	tests := []struct {
+		c       *Colour
+		noBlink bool
+		nested  bool
+		want    string
+	}{
+		{New(BlinkSlow), false, false, "\x1b[5mx\x1b[0m"},
+		{New(FgRed, BlinkRapid), false, false, "\x1b[31;6mx\x1b[0m"},
+		{New(BlinkSlow), true, false, "\x1b[7mx\x1b[0m"},
+		{New(FgRed, BlinkRapid), true, false, "\x1b[31;7mx\x1b[0m"},
+		{New(BlinkSlow), false, true, "\x1b[5mx\x1b[25m"},
+		{New(BlinkSlow), true, true, "\x1b[7mx\x1b[27m"},
+		// values inside extended colours are left alone
+		{NewFg256(5), true, false, "\x1b[38;5;5mx\x1b[0m"},
+	}
+
+	for i, tt := range tests {
+		NoBlink, NestedReset = tt.noBlink, tt.nested
+		if got := tt.c.Sprint("x"); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+	}
+}
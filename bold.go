@@ -0,0 +1,51 @@
+package colour
+
+// Bolded returns a new colour object with the given foreground and bold
+// text, the same as New(fg, Bold).
+func Bolded(fg Attribute) *Colour {
+	return New(fg, Bold)
+}
+
+// RedBold is like Red but with bold text. Its colour is cached like those of
+// the other helpers. A newline is appended to format by default.
+func RedBold(format string, a ...interface{}) {
+	printColour(GetOutput(), format, getCachedBoldColour(FgRed), a...)
+}
+
+// GreenBold is like Green but with bold text. A newline is appended to format
+// by default.
+func GreenBold(format string, a ...interface{}) {
+	printColour(GetOutput(), format, getCachedBoldColour(FgGreen), a...)
+}
+
+// YellowBold is like Yellow but with bold text. A newline is appended to
+// format by default.
+func YellowBold(format string, a ...interface{}) {
+	printColour(GetOutput(), format, getCachedBoldColour(FgYellow), a...)
+}
+
+// BlueBold is like Blue but with bold text. A newline is appended to format
+// by default.
+func BlueBold(format string, a ...interface{}) {
+	printColour(GetOutput(), format, getCachedBoldColour(FgBlue), a...)
+}
+
+// RedBoldString is like RedString but with bold text.
+func RedBoldString(format string, a ...interface{}) string {
+	return sprintColour(getCachedBoldColour(FgRed), format, a...)
+}
+
+// GreenBoldString is like GreenString but with bold text.
+func GreenBoldString(format string, a ...interface{}) string {
+	return sprintColour(getCachedBoldColour(FgGreen), format, a...)
+}
+
+// YellowBoldString is like YellowString but with bold text.
+func YellowBoldString(format string, a ...interface{}) string {
+	return sprintColour(getCachedBoldColour(FgYellow), format, a...)
+}
+
+// BlueBoldString is like BlueString but with bold text.
+func BlueBoldString(format string, a ...interface{}) string {
+	return sprintColour(getCachedBoldColour(FgBlue), format, a...)
+}
@@ -0,0 +1,57 @@
+package colour
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBoldHelpers(t *testing.T) {
+	NoColour = false
+	oldOut := GetOutput()
+	defer SetOutput(oldOut)
+
+	var buf bytes.Buffer
+	SetOutput(&buf)
+
+	RedBold("a")
+	BlueBold("%d", 1)
+	if got, want := buf.String(), "\x1b[31;1ma\n\x1b[0m\x1b[34;1m1\n\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+
+	tests := []struct {
+		got, want string
+	}{
+		{GreenBoldString("b"), "\x1b[32;1mb\x1b[0m"},
+		{YellowBoldString("%s", "c"), "\x1b[33;1mc\x1b[0m"},
+		{Bolded(FgMagenta).Sprint("d"), "\x1b[35;1md\x1b[0m"},
+	}
+
+	for i, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, tt.got)
+		}
+	}
+}
+
+func TestBoldCache(t *testing.T) {
+	ClearCache()
+	defer ClearCache()
+
+	if getCachedBoldColour(FgRed) != getCachedBoldColour(FgRed) {
+		t.Error("bold colour not cached")
+	}
+	if getCachedBoldColour(FgRed) == getCachedColour(FgRed) {
+		t.Error("bold and plain colour share a cache entry")
+	}
+	if !getCachedBoldColour(FgRed).Equals(Bolded(FgRed)) {
+		t.Error("cached bold colour is not bold")
+	}
+}
+
+func BenchmarkRedBoldString(b *testing.B) {
+	NoColour = false
+	for i := 0; i < b.N; i++ {
+		RedBoldString("benchmark")
+	}
+}
@@ -0,0 +1,41 @@
+package colour
+
+// hiOffset is the distance from the basic colours to their high intensity
+// variants, FgRed to FgHiRed or BgRed to BgHiRed.
+const hiOffset = FgHiBlack - FgBlack
+
+// Bright returns a copy of c with the basic foreground and background
+// colours, 30 to 37 and 40 to 47, replaced by their high intensity variants,
+// 90 to 97 and 100 to 107, so FgRed becomes FgHiRed. Other attributes,
+// including extended colours, are left untouched.
+func (c *Colour) Bright() *Colour {
+	return c.mapBasic(func(a Attribute) Attribute {
+		if (a >= FgBlack && a <= FgWhite) || (a >= BgBlack && a <= BgWhite) {
+			return a + hiOffset
+		}
+		return a
+	})
+}
+
+// Dim is the reverse of Bright, returning a copy of c with the high intensity
+// colours replaced by the basic ones, so FgHiRed becomes FgRed.
+func (c *Colour) Dim() *Colour {
+	return c.mapBasic(func(a Attribute) Attribute {
+		if (a >= FgHiBlack && a <= FgHiWhite) || (a >= BgHiBlack && a <= BgHiWhite) {
+			return a - hiOffset
+		}
+		return a
+	})
+}
+
+// mapBasic returns a copy of c with each attribute on its own replaced by f.
+func (c *Colour) mapBasic(f func(Attribute) Attribute) *Colour {
+	clone := c.Clone()
+	for i, p := range clone.params {
+		if a, ok := p.(basicParam); ok {
+			clone.params[i] = basicParam(f(Attribute(a)))
+		}
+	}
+
+	return clone
+}
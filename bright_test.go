@@ -0,0 +1,37 @@
+package colour
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBrightDim(t *testing.T) {
+	tests := []struct {
+		got  *Colour
+		want []Attribute
+	}{
+		{New(FgRed, BgBlue, Bold).Bright(), []Attribute{FgHiRed, BgHiBlue, Bold}},
+		{New(FgBlack, BgWhite).Bright(), []Attribute{FgHiBlack, BgHiWhite}},
+		{New(FgHiRed, Underline).Bright(), []Attribute{FgHiRed, Underline}},
+		{NewFg256(1).Add(FgGreen).Bright(), []Attribute{38, 5, 1, FgHiGreen}},
+		{New(FgHiRed, BgHiBlue, Bold).Dim(), []Attribute{FgRed, BgBlue, Bold}},
+		{New(FgHiWhite, BgHiBlack).Dim(), []Attribute{FgWhite, BgBlack}},
+		{New(FgRed, 39).Dim(), []Attribute{FgRed, 39}},
+	}
+
+	for i, tt := range tests {
+		if got := tt.got.Attributes(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("[%d] want: %v, got: %v", i, tt.want, got)
+		}
+	}
+
+	c := New(FgRed)
+	c.DisableColour()
+	b := c.Bright()
+	if !c.Equals(New(FgRed)) {
+		t.Error("Bright modified the receiver")
+	}
+	if !b.IsNoColour() {
+		t.Error("Bright lost the disabled colour setting")
+	}
+}
@@ -0,0 +1,148 @@
+package colour
+
+import (
+	"io"
+	"strings"
+	"sync"
+)
+
+// CoalescingWriter removes redundant escape sequences from coloured output
+// before passing it on, shrinking logs that print many short spans of the
+// same colour back to back. A reset immediately followed by the sequences
+// that were in effect before it, such as "\x1b[0m\x1b[31m" between two red
+// spans, is dropped, as are repeated resets. Nothing else is changed, so the
+// output renders the same.
+//
+// Sequences are held back until it is known whether they can be dropped, and
+// sequences split across writes are recognised, so call Flush after the last
+// write.
+type CoalescingWriter struct {
+	mu    sync.Mutex
+	w     io.Writer
+	buf   []byte   // input not processed yet, an incomplete sequence
+	st    sgrState // the SGR sequences in effect before a pending reset
+	reset string   // a reset held back, empty if none
+	sets  string   // SGR sequences held back after the reset
+}
+
+// NewCoalescingWriter returns a CoalescingWriter writing to w.
+func NewCoalescingWriter(w io.Writer) *CoalescingWriter {
+	return &CoalescingWriter{w: w}
+}
+
+// Write processes p and writes what can be decided on to the underlying
+// writer.
+func (cw *CoalescingWriter) Write(p []byte) (int, error) {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+
+	cw.buf = append(cw.buf, p...)
+	s := string(cw.buf)
+
+	var b strings.Builder
+	for len(s) > 0 {
+		i := strings.IndexByte(s, escape[0])
+		if i < 0 {
+			i = len(s)
+		}
+		if i > 0 {
+			cw.release(&b)
+			b.WriteString(s[:i])
+			s = s[i:]
+			continue
+		}
+
+		l := escapeLen(s)
+		if l < 0 {
+			break
+		}
+		cw.sequence(&b, s[:l])
+		s = s[l:]
+	}
+	cw.buf = append(cw.buf[:0], s...)
+
+	if b.Len() > 0 {
+		if _, err := io.WriteString(cw.w, b.String()); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Flush writes everything held back, including an incomplete sequence at
+// the end of the input.
+func (cw *CoalescingWriter) Flush() error {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+
+	var b strings.Builder
+	cw.release(&b)
+	b.Write(cw.buf)
+	cw.buf = cw.buf[:0]
+
+	if b.Len() == 0 {
+		return nil
+	}
+
+	_, err := io.WriteString(cw.w, b.String())
+	return err
+}
+
+// sequence handles the escape sequence seq, writing to b what is certain to
+// be kept.
+func (cw *CoalescingWriter) sequence(b *strings.Builder, seq string) {
+	if !isSGR(seq) {
+		cw.release(b)
+		b.WriteString(seq)
+		return
+	}
+
+	if isResetSequence(seq) {
+		if cw.reset != "" && cw.sets == "" {
+			// a second reset in a row changes nothing
+			return
+		}
+		cw.release(b)
+		cw.reset = seq
+		return
+	}
+
+	if cw.reset == "" {
+		cw.st.update(seq)
+		b.WriteString(seq)
+		return
+	}
+
+	sets := cw.sets + seq
+	switch {
+	case sets == cw.st.active:
+		// the style before the reset is restored, drop both
+		cw.reset, cw.sets = "", ""
+	case strings.HasPrefix(cw.st.active, sets):
+		cw.sets = sets
+	default:
+		cw.sets = sets
+		cw.release(b)
+	}
+}
+
+// release writes the pending reset and the sequences following it to b.
+func (cw *CoalescingWriter) release(b *strings.Builder) {
+	if cw.reset == "" {
+		return
+	}
+
+	b.WriteString(cw.reset)
+	b.WriteString(cw.sets)
+	cw.st = sgrState{}
+	cw.st.feed(cw.sets)
+	cw.reset, cw.sets = "", ""
+}
+
+// isResetSequence reports whether the SGR sequence seq resets all attributes
+// and nothing else.
+func isResetSequence(seq string) bool {
+	p := seq[2 : len(seq)-1]
+	return p == "" || strings.Trim(p, "0") == ""
+}
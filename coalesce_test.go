@@ -0,0 +1,67 @@
+package colour
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCoalescingWriter(t *testing.T) {
+	tests := []struct {
+		writes []string
+		want   string
+	}{
+		{[]string{"\x1b[31ma\x1b[0m\x1b[31mb\x1b[0m"}, "\x1b[31mab\x1b[0m"},
+		{[]string{"\x1b[31ma\x1b[0m", "\x1b[31mb\x1b[0m", "\x1b[31mc\x1b[0m\n"}, "\x1b[31mabc\x1b[0m\n"},
+		// a different colour is kept
+		{[]string{"\x1b[31ma\x1b[0m\x1b[32mb\x1b[0m"}, "\x1b[31ma\x1b[0m\x1b[32mb\x1b[0m"},
+		// text between the spans
+		{[]string{"\x1b[31ma\x1b[0m \x1b[31mb\x1b[0m"}, "\x1b[31ma\x1b[0m \x1b[31mb\x1b[0m"},
+		// several sequences restored
+		{[]string{"\x1b[1m\x1b[31ma\x1b[0m\x1b[1m\x1b[31mb\x1b[0m"}, "\x1b[1m\x1b[31mab\x1b[0m"},
+		// only part of the style restored
+		{[]string{"\x1b[1m\x1b[31ma\x1b[0m\x1b[1mb\x1b[0m"}, "\x1b[1m\x1b[31ma\x1b[0m\x1b[1mb\x1b[0m"},
+		// the style restored and more added on top
+		{[]string{"\x1b[1ma\x1b[0m\x1b[1m\x1b[31mb\x1b[0m"}, "\x1b[1ma\x1b[31mb\x1b[0m"},
+		// sequences split across writes
+		{[]string{"\x1b[31ma\x1b[", "0m\x1b", "[31mb\x1b[0m"}, "\x1b[31mab\x1b[0m"},
+		// repeated resets
+		{[]string{"a\x1b[0m\x1b[m\x1b[0mb"}, "a\x1b[0mb"},
+		// other sequences are kept in place
+		{[]string{"\x1b[31ma\x1b[0m\x1b[K\x1b[31mb\x1b[0m"}, "\x1b[31ma\x1b[0m\x1b[K\x1b[31mb\x1b[0m"},
+		// held back until flushed
+		{[]string{"\x1b[31ma\x1b[0m"}, "\x1b[31ma\x1b[0m"},
+		{[]string{"a\x1b[3"}, "a\x1b[3"},
+	}
+
+	for i, tt := range tests {
+		var buf bytes.Buffer
+		cw := NewCoalescingWriter(&buf)
+		for _, s := range tt.writes {
+			if n, err := cw.Write([]byte(s)); n != len(s) || err != nil {
+				t.Fatalf("[%d] Write returned %d, %v", i, n, err)
+			}
+		}
+		if err := cw.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+	}
+}
+
+func TestCoalescingWriterColours(t *testing.T) {
+	NoColour = false
+
+	var buf bytes.Buffer
+	cw := NewCoalescingWriter(&buf)
+	red := New(FgRed)
+	for _, s := range []string{"a", "b", "c"} {
+		red.Fprint(cw, s)
+	}
+	cw.Flush()
+
+	if got, want := buf.String(), "\x1b[31mabc\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+}
@@ -4,39 +4,161 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"strconv"
 	"strings"
 	"sync"
 
 	"github.com/mattn/go-colorable"
-	"github.com/mattn/go-isatty"
 )
 
 var (
 	// NoColour defines if the output is colourized or not. It's dynamically set to
 	// false or true based on the stdout's file descriptor referring to a terminal
-	// or not. This is a global option and affects all colours. For more control
-	// over each colour block use the methods DisableColour() individually.
-	NoColour = os.Getenv("TERM") == "dumb" ||
-		(!isatty.IsTerminal(os.Stdout.Fd()) && !isatty.IsCygwinTerminal(os.Stdout.Fd()))
+	// or not. The NO_COLOR and FORCE_COLOR environment variables override the
+	// detection, see SupportsColour. This is a global option and affects all
+	// colours. For more control over each colour block use the methods
+	// DisableColour() individually. Use SetNoColour to change it while other
+	// goroutines may be printing.
+	NoColour = !SupportsColour(os.Stdout)
 
 	// Output defines the standard output of the print functions. By default
-	// os.Stdout is used.
+	// os.Stdout is used. Use SetOutput to change it while other goroutines
+	// may be printing.
 	Output = colorable.NewColorableStdout()
 
-	// Error defines a colour supporting writer for os.Stderr.
+	// Error defines a colour supporting writer for os.Stderr. Use SetError to
+	// change it while other goroutines may be printing.
 	Error = colorable.NewColorableStderr()
 
+	// noColourMu protects NoColour, see SetNoColour and GetNoColour.
+	noColourMu sync.RWMutex
+
+	// outputMu protects Output and Error, see SetOutput and SetError.
+	outputMu sync.RWMutex
+
 	// coloursCache is used to reduce the count of created Colour objects and
-	// allows to reuse already created objects with required Attribute.
-	coloursCache   = make(map[Attribute]*Colour)
-	coloursCacheMu sync.Mutex // protects coloursCache
+	// allows to reuse already created objects with required Attribute. It
+	// holds at most maxCachedColours entries.
+	coloursCache   = make(map[cacheKey]*Colour)
+	fg256Cache     = make(map[uint8]*Colour) // colours of Fg256String
+	coloursCacheMu sync.Mutex                // protects coloursCache and fg256Cache
 )
 
+// detectNoColour reports whether colour should be disabled for output going
+// to a terminal or not. FORCE_COLOR takes precedence, turning colour off when
+// set to "0" or "false" and on otherwise, so that colour can be forced for a
+// single command even if NO_COLOR is set globally. Next a non-empty NO_COLOR
+// disables colour, see https://no-color.org.
+func detectNoColour(isTerminal bool) bool {
+	if on, _, ok := forceColourLevel(); ok {
+		return !on
+	}
+
+	if os.Getenv("NO_COLOR") != "" {
+		return true
+	}
+
+	return os.Getenv("TERM") == "dumb" || !isTerminal
+}
+
+// SetNoColour sets NoColour in a way that is safe for concurrent use with
+// printing.
+func SetNoColour(v bool) {
+	noColourMu.Lock()
+	defer noColourMu.Unlock()
+	NoColour = v
+}
+
+// GetNoColour returns NoColour. All reads of NoColour within the package go
+// through it.
+func GetNoColour() bool {
+	noColourMu.RLock()
+	defer noColourMu.RUnlock()
+	return NoColour
+}
+
+// colourDisabled reports whether colour is disabled for colours without a
+// setting of their own, either by NoColour or by a colour level of
+// LevelNone, which cannot display any colour.
+func colourDisabled() bool {
+	return GetNoColour() || GetLevel() == LevelNone
+}
+
+// ReconfigureFromOutput recomputes NoColour for the current Output, for use
+// after Output was replaced, for example with a file. The environment is
+// taken into account as at start up. Only writers exposing a file
+// descriptor, such as *os.File, can be checked, for others NoColour is left
+// unchanged. Call it after Output is set and before anything is printed, and
+// before applying flags such as --no-colour, which it would otherwise
+// override.
+func ReconfigureFromOutput() {
+	w := GetOutput()
+	if _, ok := w.(interface{ Fd() uintptr }); !ok {
+		return
+	}
+
+	SetNoColour(!SupportsColour(w))
+}
+
+// SetOutput sets Output, the writer of the print functions, in a way that is
+// safe for concurrent use with printing. Files are wrapped with colorable so
+// colours keep working on Windows.
+func SetOutput(w io.Writer) {
+	w = colorableFile(w)
+
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	Output = w
+}
+
+// GetOutput returns Output, the writer of the print functions.
+func GetOutput() io.Writer {
+	outputMu.RLock()
+	defer outputMu.RUnlock()
+	return Output
+}
+
+// SetError sets Error, the colour supporting writer for standard error, in a
+// way that is safe for concurrent use with printing. Files are wrapped with
+// colorable so colours keep working on Windows.
+func SetError(w io.Writer) {
+	w = colorableFile(w)
+
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	Error = w
+}
+
+// GetError returns Error, the colour supporting writer for standard error.
+func GetError() io.Writer {
+	outputMu.RLock()
+	defer outputMu.RUnlock()
+	return Error
+}
+
+// colorableFile wraps w with colorable if it is a file.
+func colorableFile(w io.Writer) io.Writer {
+	if f, ok := w.(*os.File); ok {
+		return colorable.NewColorable(f)
+	}
+
+	return w
+}
+
 // Colour defines a custom colour object which is defined by SGR parameters.
+//
+// A colour may be changed with methods such as Add or Remove while other
+// goroutines print with it, which happens with the shared colours returned by
+// the cached helpers. These methods never modify the parameters in place but
+// replace them with a new slice, so output uses either the old or the new
+// attributes, never a mix. To derive a variant without affecting others
+// printing with a shared colour, change a Clone instead.
 type Colour struct {
-	params   []Attribute
-	noColour *bool
+	mu          sync.RWMutex // protects the fields below
+	params      []param      // replaced as a whole, never modified in place
+	noColour    *bool
+	alwaysReset bool
+	gen         uint64      // incremented when params or alwaysReset change
+	cache       *affixCache // built by cachedAffixes
 }
 
 // Attribute defines a single SGR Code
@@ -58,6 +180,30 @@ const (
 	CrossedOut
 )
 
+// Overline draws a line above the text. It is not supported by every
+// terminal.
+const Overline Attribute = 53
+
+// Rarely supported attributes
+const (
+	Framed    Attribute = 51
+	Encircled Attribute = 52
+)
+
+// Font selection, rarely supported
+const (
+	PrimaryFont Attribute = iota + 10
+	Font1
+	Font2
+	Font3
+	Font4
+	Font5
+	Font6
+	Font7
+	Font8
+	Font9
+)
+
 // Foreground text colours
 const (
 	FgBlack Attribute = iota + 30
@@ -106,13 +252,31 @@ const (
 	BgHiWhite
 )
 
+// Plain is a colour that applies no styling at all, for APIs taking a colour
+// when no styling is wanted. It prints its arguments as plain text whatever
+// the value of NoColour. It is shared, so it must not be modified.
+var Plain = &Colour{params: []param{}, noColour: boolPtr(true)}
+
 // New returns a newly created colour object.
 func New(value ...Attribute) *Colour {
-	c := &Colour{params: make([]Attribute, 0)}
+	c := &Colour{params: make([]param, 0)}
 	c.Add(value...)
 	return c
 }
 
+// NewIf is like New but returns a colour with colour output disabled, as if
+// DisableColour was called, when enabled is false. Such a colour prints its
+// arguments as plain text. When enabled is true, the global NoColour setting
+// applies as usual.
+func NewIf(enabled bool, value ...Attribute) *Colour {
+	c := New(value...)
+	if !enabled {
+		c.DisableColour()
+	}
+
+	return c
+}
+
 // Set sets the given parameters immediately. It will change the colour of
 // output with the given SGR parameters until colour.Unset() is called.
 func Set(p ...Attribute) *Colour {
@@ -124,21 +288,46 @@ func Set(p ...Attribute) *Colour {
 // Unset resets all escape attributes and clears the output. Usually should
 // be called after Set().
 func Unset() {
-	if NoColour {
+	if colourDisabled() {
 		return
 	}
 
-	fmt.Fprintf(Output, "%s[%dm", escape, Reset)
+	fmt.Fprintf(GetOutput(), "%s[%dm", escape, Reset)
 }
 
-// Set sets the SGR sequence.
-func (c *Colour) Set() *Colour {
-	if c.isNoColourSet() {
-		return c
+// SetWriter is like Set but writes the SGR sequence to w instead of Output.
+// The colour stays in effect on w until UnsetWriter(w) is called.
+func SetWriter(w io.Writer, p ...Attribute) *Colour {
+	return New(p...).setWriter(w)
+}
+
+// UnsetWriter is like Unset but writes the reset to w instead of Output.
+func UnsetWriter(w io.Writer) {
+	if colourDisabled() {
+		return
 	}
 
-	fmt.Fprintf(Output, c.format())
-	return c
+	fmt.Fprintf(w, "%s[%dm", escape, Reset)
+}
+
+// Setf is like Set but writes the SGR sequence to w instead of Output. The
+// returned function writes the matching reset to w, so scopes can be closed
+// with a defer:
+//
+//	restore := colour.Setf(w, colour.FgRed)
+//	defer restore()
+func Setf(w io.Writer, p ...Attribute) (restore func()) {
+	c := New(p...)
+	c.setWriter(w)
+	return func() {
+		c.unsetWriter(w)
+	}
+}
+
+// Set sets the SGR sequence. Nothing is written if colour is disabled or c
+// has no attributes.
+func (c *Colour) Set() *Colour {
+	return c.setWriter(GetOutput())
 }
 
 func (c *Colour) unset() {
@@ -146,53 +335,174 @@ func (c *Colour) unset() {
 		return
 	}
 
-	Unset()
+	c.unsetWriter(GetOutput())
 }
 
 func (c *Colour) setWriter(w io.Writer) *Colour {
-	if c.isNoColourSet() {
-		return c
+	if prefix, _ := c.affixes(); prefix != "" {
+		fmt.Fprint(w, prefix)
 	}
 
-	fmt.Fprintf(w, c.format())
 	return c
 }
 
 func (c *Colour) unsetWriter(w io.Writer) {
-	if c.isNoColourSet() {
+	_, suffix := c.affixes()
+	if suffix == "" {
 		return
 	}
 
-	if NoColour {
+	if colourDisabled() {
 		return
 	}
 
-	fmt.Fprintf(w, "%s[%dm", escape, Reset)
+	fmt.Fprint(w, suffix)
 }
 
 // Add is used to chain SGR parameters. Use as many as parameters to combine
 // and create custom colour objects. Example: Add(colour.FgRed, colour.Underline).
+// Extended colours such as 38, 5, n must be given within a single call.
 func (c *Colour) Add(value ...Attribute) *Colour {
-	c.params = append(c.params, value...)
+	c.mu.Lock()
+	c.params = appendParams(c.params[:len(c.params):len(c.params)], value)
+	c.gen++
+	c.mu.Unlock()
+
 	return c
 }
 
+// Remove deletes all occurrences of the given SGR parameters from the colour
+// and returns it for chaining. Extended colours are removed as a whole when
+// their leading parameter is given, the values inside them are never matched
+// on their own. Parameters not present are ignored.
+func (c *Colour) Remove(value ...Attribute) *Colour {
+	c.mu.Lock()
+	params := make([]param, 0, len(c.params))
+	for _, p := range c.params {
+		if !attrIn(p.attrs()[0], value) {
+			params = append(params, p)
+		}
+	}
+	c.params = params
+	c.gen++
+	c.mu.Unlock()
+
+	return c
+}
+
+func attrIn(a Attribute, list []Attribute) bool {
+	for _, v := range list {
+		if v == a {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Clone returns a copy of the colour which can be changed without affecting
+// the original, for example to derive variants of a base style.
+func (c *Colour) Clone() *Colour {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	clone := &Colour{params: make([]param, len(c.params)), alwaysReset: c.alwaysReset}
+	copy(clone.params, c.params)
+	if c.noColour != nil {
+		clone.noColour = boolPtr(*c.noColour)
+	}
+
+	return clone
+}
+
+// Attributes returns a copy of the SGR parameters of the colour in the order
+// they were added. Changing the returned slice does not affect the colour.
+func (c *Colour) Attributes() []Attribute {
+	params := c.paramList()
+	attrs := make([]Attribute, 0, len(params))
+	for _, p := range params {
+		attrs = append(attrs, p.attrs()...)
+	}
+	return attrs
+}
+
+// IsEmpty reports whether c has no attributes, so it prints its arguments
+// without styling. Unlike IsNoColour it does not take into account whether
+// colour is disabled.
+func (c *Colour) IsEmpty() bool {
+	return len(c.paramList()) == 0
+}
+
+// paramList returns the parameters of c. The slice is never modified, as
+// changes to c replace it, so it can be used without holding the lock.
+func (c *Colour) paramList() []param {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.params
+}
+
+// Merge returns a new colour holding the attributes of both c and other, for
+// example to combine a foreground with a separately kept set of attributes
+// such as bold and underline. Attributes held by both appear once. The
+// foreground, background and underline colour can only have one value each,
+// so where both colours set one of them, the one of other wins:
+// New(FgRed, Bold).Merge(New(FgBlue)) is New(Bold, FgBlue). The result
+// disables colour if c does.
+func (c *Colour) Merge(other *Colour) *Colour {
+	var overridden [3]bool
+	for _, p := range other.paramList() {
+		if k := colourKind(p.attrs()); k >= 0 {
+			overridden[k] = true
+		}
+	}
+
+	merged := c.Clone()
+	merged.params = merged.params[:0]
+	for _, ps := range [][]param{c.paramList(), other.paramList()} {
+		for _, p := range ps {
+			k := colourKind(p.attrs())
+			if (k >= 0 && overridden[k] && !other.hasParam(p)) || merged.hasParam(p) {
+				continue
+			}
+			merged.params = append(merged.params, p)
+		}
+	}
+
+	return merged
+}
+
+// colourKind returns 0, 1 or 2 if the parameter group g sets the foreground,
+// background or underline colour, or -1 if it does none of them.
+func colourKind(g []Attribute) int {
+	switch {
+	case isForeground(g) || g[0] == 39:
+		return 0
+	case isBackground(g) || g[0] == 49:
+		return 1
+	case g[0] == extendedUnderlineColour || g[0] == DefaultUnderlineColour:
+		return 2
+	}
+
+	return -1
+}
+
 func (c *Colour) prepend(value Attribute) {
-	c.params = append(c.params, 0)
-	copy(c.params[1:], c.params[0:])
-	c.params[0] = value
+	c.mu.Lock()
+	c.params = append([]param{basicParam(value)}, c.params...)
+	c.gen++
+	c.mu.Unlock()
 }
 
 // Fprint formats using the default formats for its operands and writes to w.
 // Spaces are added between operands when neither is a string.
 // It returns the number of bytes written and any write error encountered.
-// On Windows, users should wrap w with colorable.NewColorable() if w is of
-// type *os.File.
+// On Windows, w is wrapped with colorable if it is an *os.File.
 func (c *Colour) Fprint(w io.Writer, a ...interface{}) (n int, err error) {
+	w = autoColorable(w)
 	c.setWriter(w)
 	defer c.unsetWriter(w)
 
-	return fmt.Fprint(w, a...)
+	return fprint(w, a...)
 }
 
 // Print formats using the default formats for its operands and writes to
@@ -204,39 +514,69 @@ func (c *Colour) Print(a ...interface{}) (n int, err error) {
 	c.Set()
 	defer c.unset()
 
-	return fmt.Fprint(Output, a...)
+	return fprint(GetOutput(), a...)
 }
 
 // Fprintf formats according to a format specifier and writes to w.
 // It returns the number of bytes written and any write error encountered.
-// On Windows, users should wrap w with colorable.NewColorable() if w is of
-// type *os.File.
+// On Windows, w is wrapped with colorable if it is an *os.File.
 func (c *Colour) Fprintf(w io.Writer, format string, a ...interface{}) (n int, err error) {
+	if err := strictFormatError(format, len(a)); err != nil {
+		return 0, err
+	}
+
+	w = autoColorable(w)
 	c.setWriter(w)
 	defer c.unsetWriter(w)
 
-	return fmt.Fprintf(w, format, a...)
+	return fprintf(w, format, a...)
 }
 
 // Printf formats according to a format specifier and writes to standard output.
 // It returns the number of bytes written and any write error encountered.
 // This is the standard fmt.Printf() method wrapped with the given colour.
 func (c *Colour) Printf(format string, a ...interface{}) (n int, err error) {
+	if err := strictFormatError(format, len(a)); err != nil {
+		return 0, err
+	}
+
 	c.Set()
 	defer c.unset()
 
-	return fmt.Fprintf(Output, format, a...)
+	return fprintf(GetOutput(), format, a...)
 }
 
 // Fprintln formats using the default formats for its operands and writes to w.
 // Spaces are always added between operands and a newline is appended.
-// On Windows, users should wrap w with colorable.NewColorable() if w is of
-// type *os.File.
+// On Windows, w is wrapped with colorable if it is an *os.File.
 func (c *Colour) Fprintln(w io.Writer, a ...interface{}) (n int, err error) {
+	w = autoColorable(w)
 	c.setWriter(w)
 	defer c.unsetWriter(w)
 
-	return fmt.Fprintln(w, a...)
+	return fprintln(w, a...)
+}
+
+// FprintCounted is like Fprint but reports the bytes written separately: n
+// counts the bytes of the formatted arguments only and total those of the
+// escape sequences too, so the overhead of colour is total - n. Unlike
+// Fprint it writes the output with a single call to w. If the write fails
+// part way, n counts the argument bytes that made it.
+func (c *Colour) FprintCounted(w io.Writer, a ...interface{}) (n, total int, err error) {
+	s := sanitize(fmt.Sprint(a...))
+	prefix, suffix := c.Wrap()
+
+	total, err = io.WriteString(autoColorable(w), prefix+s+suffix)
+
+	n = total - len(prefix)
+	switch {
+	case n < 0:
+		n = 0
+	case n > len(s):
+		n = len(s)
+	}
+
+	return n, total, err
 }
 
 // Println formats using the default formats for its operands and writes to
@@ -248,7 +588,7 @@ func (c *Colour) Println(a ...interface{}) (n int, err error) {
 	c.Set()
 	defer c.unset()
 
-	return fmt.Fprintln(Output, a...)
+	return fprintln(GetOutput(), a...)
 }
 
 // Sprint is just like Print, but returns a string instead of printing it.
@@ -256,6 +596,12 @@ func (c *Colour) Sprint(a ...interface{}) string {
 	return c.wrap(fmt.Sprint(a...))
 }
 
+// SprintBytes is like Sprint for a single byte slice, treating it as text.
+// Sprint formats byte slices as a list of numbers, as fmt.Sprint does.
+func (c *Colour) SprintBytes(b []byte) string {
+	return c.wrap(string(b))
+}
+
 // Sprintln is just like Println, but returns a string instead of printing it.
 func (c *Colour) Sprintln(a ...interface{}) string {
 	return c.wrap(fmt.Sprintln(a...))
@@ -263,9 +609,42 @@ func (c *Colour) Sprintln(a ...interface{}) string {
 
 // Sprintf is just like Printf, but returns a string instead of printing it.
 func (c *Colour) Sprintf(format string, a ...interface{}) string {
+	if err := strictFormatError(format, len(a)); err != nil {
+		return err.Error()
+	}
+
 	return c.wrap(fmt.Sprintf(format, a...))
 }
 
+// SprintReset is like Sprint but ends by restoring the style of reset rather
+// than the terminal default, for example the base background of an
+// application: the reset is followed by the sequence of reset. If reset is
+// nil or has colour disabled it behaves like Sprint.
+func (c *Colour) SprintReset(reset *Colour, a ...interface{}) string {
+	s := c.Sprint(a...)
+	if reset == nil || reset.isNoColourSet() || c.isNoColourSet() {
+		return s
+	}
+
+	return s + reset.format()
+}
+
+// SprintLines is like Sprint for a multi-line string, but colours each line
+// on its own: the colour is reapplied after every newline and reset before
+// it. Pagers such as less reset colours at line boundaries, which otherwise
+// leaves all but the first line plain. Empty lines, including after a
+// trailing newline, are left empty.
+func (c *Colour) SprintLines(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = c.wrap(line)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
 // FprintFunc returns a new function that prints the passed arguments as
 // colourized with colour.Fprint().
 func (c *Colour) FprintFunc() func(w io.Writer, a ...interface{}) {
@@ -286,6 +665,9 @@ func (c *Colour) PrintFunc() func(a ...interface{}) {
 // colourized with colour.Fprintf().
 func (c *Colour) FprintfFunc() func(w io.Writer, format string, a ...interface{}) {
 	return func(w io.Writer, format string, a ...interface{}) {
+		if writeFormatError(w, format, len(a), "") {
+			return
+		}
 		c.Fprintf(w, format, a...)
 	}
 }
@@ -294,6 +676,9 @@ func (c *Colour) FprintfFunc() func(w io.Writer, format string, a ...interface{}
 // colourized with colour.Printf().
 func (c *Colour) PrintfFunc() func(format string, a ...interface{}) {
 	return func(format string, a ...interface{}) {
+		if writeFormatError(GetOutput(), format, len(a), "") {
+			return
+		}
 		c.Printf(format, a...)
 	}
 }
@@ -314,6 +699,36 @@ func (c *Colour) PrintlnFunc() func(a ...interface{}) {
 	}
 }
 
+// BoundFprintFunc is like FprintFunc but binds the writer once, returning a
+// function that prints the passed arguments to w as colourized with
+// colour.Fprint().
+func (c *Colour) BoundFprintFunc(w io.Writer) func(a ...interface{}) {
+	return func(a ...interface{}) {
+		c.Fprint(w, a...)
+	}
+}
+
+// BoundFprintfFunc is like FprintfFunc but binds the writer once, returning a
+// function that prints the passed arguments to w as colourized with
+// colour.Fprintf().
+func (c *Colour) BoundFprintfFunc(w io.Writer) func(format string, a ...interface{}) {
+	return func(format string, a ...interface{}) {
+		if writeFormatError(w, format, len(a), "") {
+			return
+		}
+		c.Fprintf(w, format, a...)
+	}
+}
+
+// BoundFprintlnFunc is like FprintlnFunc but binds the writer once, returning
+// a function that prints the passed arguments to w as colourized with
+// colour.Fprintln().
+func (c *Colour) BoundFprintlnFunc(w io.Writer) func(a ...interface{}) {
+	return func(a ...interface{}) {
+		c.Fprintln(w, a...)
+	}
+}
+
 // SprintFunc returns a new function that returns colourized strings for the
 // given arguments with fmt.Sprint(). Useful to put into or mix into other
 // string. Windows users should use this in conjunction with colour.Output, example:
@@ -322,16 +737,48 @@ func (c *Colour) PrintlnFunc() func(a ...interface{}) {
 //	fmt.Fprintf(colour.Output, "This is a %s", put("warning"))
 func (c *Colour) SprintFunc() func(a ...interface{}) string {
 	return func(a ...interface{}) string {
+		if len(a) == 1 && c.isNoColourSet() {
+			if s, ok := a[0].(string); ok {
+				return sanitize(s)
+			}
+		}
+
 		return c.wrap(fmt.Sprint(a...))
 	}
 }
 
+// SprintStringFunc is like SprintFunc for a single string. It avoids the
+// cost of formatting arguments, which makes it the fastest way to colour
+// strings and free of allocations when colour is disabled.
+func (c *Colour) SprintStringFunc() func(s string) string {
+	return func(s string) string {
+		return c.wrap(s)
+	}
+}
+
+// SprintFuncCached is like SprintFunc but builds the escape sequences once,
+// when it is called, instead of on every call of the returned function. Use it
+// in hot paths. Attributes added to the colour or a change of colour level
+// afterwards are not reflected by the returned function, disabling colour is.
+func (c *Colour) SprintFuncCached() func(a ...interface{}) string {
+	prefix, suffix := c.enabledAffixes()
+
+	return func(a ...interface{}) string {
+		s := sanitize(fmt.Sprint(a...))
+		if c.isNoColourSet() {
+			return s
+		}
+
+		return prefix + s + suffix
+	}
+}
+
 // SprintfFunc returns a new function that returns colourized strings for the
 // given arguments with fmt.Sprintf(). Useful to put into or mix into other
 // string. Windows users should use this in conjunction with colour.Output.
 func (c *Colour) SprintfFunc() func(format string, a ...interface{}) string {
 	return func(format string, a ...interface{}) string {
-		return c.wrap(fmt.Sprintf(format, a...))
+		return c.Sprintf(format, a...)
 	}
 }
 
@@ -345,24 +792,95 @@ func (c *Colour) SprintlnFunc() func(a ...interface{}) string {
 }
 
 // sequence returns a formatted SGR sequence to be plugged into a "\x1b[...m"
-// an example output might be: "1;36" -> bold cyan
+// an example output might be: "1;36" -> bold cyan. Extended colours are
+// downsampled to the current colour level.
 func (c *Colour) sequence() string {
-	format := make([]string, len(c.params))
-	for i, v := range c.params {
-		format[i] = strconv.Itoa(int(v))
+	level := GetLevel()
+	groups := c.groups()
+	format := make([]string, 0, len(groups))
+	for _, g := range groups {
+		for _, v := range substitute(downsample(g, level)) {
+			format = append(format, attrParam(v))
+		}
 	}
 
 	return strings.Join(format, ";")
 }
 
+// String returns the SGR parameters of the colour for debugging, such as
+// "Colour(1;31)", or "Colour(<none>)" if it has no attributes. The parameters
+// are given as added, regardless of the colour level, and no escape bytes are
+// included, so the result is safe to log.
+func (c *Colour) String() string {
+	if c.IsEmpty() {
+		return "Colour(<none>)"
+	}
+
+	attrs := c.Attributes()
+	params := make([]string, len(attrs))
+	for i, a := range attrs {
+		params[i] = attrParam(a)
+	}
+
+	return "Colour(" + strings.Join(params, ";") + ")"
+}
+
+// Wrap returns the escape sequence turning the colour on and the one turning
+// it off again, for applying the colour around content that is built
+// piecewise, such as in templates. Both are empty if colour is disabled or c
+// has no attributes, though see AlwaysReset.
+func (c *Colour) Wrap() (prefix, suffix string) {
+	return c.affixes()
+}
+
 // wrap wraps the s string with the colours attributes. The string is ready to
-// be printed.
+// be printed. It is given the formatted arguments only, so this is also where
+// they are sanitized. A colour without attributes leaves s as it is rather
+// than wrapping it in empty sequences, unless AlwaysReset is set.
 func (c *Colour) wrap(s string) string {
+	s = sanitize(s)
+	prefix, suffix := c.affixes()
+
+	return prefix + s + suffix
+}
+
+// affixes returns the sequences written before and after the arguments,
+// both empty if colour is disabled.
+func (c *Colour) affixes() (prefix, suffix string) {
 	if c.isNoColourSet() {
-		return s
+		return "", ""
 	}
 
-	return c.format() + s + c.unformat()
+	return c.enabledAffixes()
+}
+
+// enabledAffixes is like affixes but ignores whether colour is disabled. A
+// colour without attributes has none, or only the reset if AlwaysReset is
+// set.
+func (c *Colour) enabledAffixes() (prefix, suffix string) {
+	if c.IsEmpty() {
+		c.mu.RLock()
+		alwaysReset := c.alwaysReset
+		c.mu.RUnlock()
+
+		if alwaysReset {
+			return "", c.unformat()
+		}
+		return "", ""
+	}
+
+	return c.startSequence(), c.unformat()
+}
+
+// AlwaysReset controls whether output of c always ends with a reset while
+// colour is enabled, even if c has no attributes, for parsers expecting every
+// coloured token to be terminated explicitly. By default a colour without
+// attributes prints its arguments as they are.
+func (c *Colour) AlwaysReset(enable bool) {
+	c.mu.Lock()
+	c.alwaysReset = enable
+	c.gen++
+	c.mu.Unlock()
 }
 
 func (c *Colour) format() string {
@@ -370,6 +888,10 @@ func (c *Colour) format() string {
 }
 
 func (c *Colour) unformat() string {
+	if NestedReset {
+		return fmt.Sprintf("%s[%sm", escape, c.cancelSequence())
+	}
+
 	return fmt.Sprintf("%s[%dm", escape, Reset)
 }
 
@@ -377,33 +899,58 @@ func (c *Colour) unformat() string {
 // code and still being able to output. Can be used for flags like
 // "--no-colour". To enable back use EnableColour() method.
 func (c *Colour) DisableColour() {
-	c.noColour = boolPtr(true)
+	c.setNoColour(boolPtr(true))
 }
 
 // EnableColour enables the colour output. Use it in conjunction with
 // DisableColour(). Otherwise this method has no side effects.
 func (c *Colour) EnableColour() {
-	c.noColour = boolPtr(false)
+	c.setNoColour(boolPtr(false))
+}
+
+func (c *Colour) setNoColour(v *bool) {
+	c.mu.Lock()
+	c.noColour = v
+	c.mu.Unlock()
+}
+
+// ownNoColour returns the value set with DisableColour or EnableColour, nil
+// if neither was called.
+func (c *Colour) ownNoColour() *bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.noColour
+}
+
+// IsNoColour reports whether colour output is disabled for c, either by
+// DisableColour or, unless EnableColour was called, by the global NoColour.
+func (c *Colour) IsNoColour() bool {
+	return c.isNoColourSet()
 }
 
 func (c *Colour) isNoColourSet() bool {
 	// check first if we have user setted action
-	if c.noColour != nil {
-		return *c.noColour
+	if v := c.ownNoColour(); v != nil {
+		return *v
 	}
 
 	// if not return the global option, which is disabled by default
-	return NoColour
+	return colourDisabled()
 }
 
 // Equals returns a boolean value indicating whether two colours are equal.
+// Colours are compared by the set of attributes they hold, so the order in
+// which attributes were added and attributes added more than once make no
+// difference: New(FgRed, Bold) equals New(Bold, FgRed, FgRed).
 func (c *Colour) Equals(c2 *Colour) bool {
-	if len(c.params) != len(c2.params) {
-		return false
+	for _, p := range c.paramList() {
+		if !c2.hasParam(p) {
+			return false
+		}
 	}
 
-	for _, attr := range c.params {
-		if !c2.attrExists(attr) {
+	for _, p := range c2.paramList() {
+		if !c.hasParam(p) {
 			return false
 		}
 	}
@@ -411,9 +958,9 @@ func (c *Colour) Equals(c2 *Colour) bool {
 	return true
 }
 
-func (c *Colour) attrExists(a Attribute) bool {
-	for _, attr := range c.params {
-		if attr == a {
+func (c *Colour) hasParam(p param) bool {
+	for _, q := range c.paramList() {
+		if q == p {
 			return true
 		}
 	}
@@ -425,36 +972,90 @@ func boolPtr(v bool) *bool {
 	return &v
 }
 
+// maxCachedColours bounds the number of colours kept by getCachedColour.
+const maxCachedColours = 256
+
+// getCachedColour returns the shared colour for p. Cached colours must never
+// have their own noColour set, so they keep following the global NoColour.
 func getCachedColour(p Attribute) *Colour {
+	return cachedColour(cacheKey{p: p})
+}
+
+// getCachedBoldColour is like getCachedColour but returns the shared colour
+// for p combined with Bold.
+func getCachedBoldColour(p Attribute) *Colour {
+	return cachedColour(cacheKey{p: p, bold: true})
+}
+
+// cacheKey identifies a colour of coloursCache: an attribute, optionally
+// combined with Bold.
+type cacheKey struct {
+	p    Attribute
+	bold bool
+}
+
+func cachedColour(k cacheKey) *Colour {
 	coloursCacheMu.Lock()
 	defer coloursCacheMu.Unlock()
 
-	c, ok := coloursCache[p]
+	c, ok := coloursCache[k]
 	if !ok {
-		c = New(p)
-		coloursCache[p] = c
+		c = New(k.p)
+		if k.bold {
+			c.Add(Bold)
+		}
+		if len(coloursCache) < maxCachedColours {
+			coloursCache[k] = c
+		}
 	}
 
 	return c
 }
 
-func colourPrint(format string, p Attribute, a ...interface{}) {
-	c := getCachedColour(p)
+// ClearCache drops the colours cached by the helper functions such as Red,
+// RedString and Fg256String. The cached colours never capture the NoColour
+// setting, they read it each time they print, so changing NoColour at
+// runtime takes effect without clearing the cache. It is there to release
+// memory and to start afresh, for example in tests.
+func ClearCache() {
+	coloursCacheMu.Lock()
+	defer coloursCacheMu.Unlock()
+
+	coloursCache = make(map[cacheKey]*Colour)
+	fg256Cache = make(map[uint8]*Colour)
+}
 
+func colourPrint(w io.Writer, format string, p Attribute, a ...interface{}) {
+	printColour(w, format, getCachedColour(p), a...)
+}
+
+// printColour prints like the print helpers, appending a newline to format
+// unless it has one.
+func printColour(w io.Writer, format string, c *Colour, a ...interface{}) {
+	if len(a) > 0 && writeFormatError(w, format, len(a), "\n") {
+		return
+	}
 	if !strings.HasSuffix(format, "\n") {
 		format += "\n"
 	}
 
+	c.setWriter(w)
+	defer c.unsetWriter(w)
+
 	if len(a) == 0 {
-		c.Print(format)
+		fprint(w, format)
 	} else {
-		c.Printf(format, a...)
+		fprintf(w, format, a...)
 	}
 }
 
 func colourString(format string, p Attribute, a ...interface{}) string {
-	c := getCachedColour(p)
+	return sprintColour(getCachedColour(p), format, a...)
+}
 
+// sprintColour formats like the String helpers, using format as is when there
+// are no arguments.
+func sprintColour(c *Colour, format string, a ...interface{}) string {
 	if len(a) == 0 {
 		return c.SprintFunc()(format)
 	}
@@ -464,35 +1065,67 @@ func colourString(format string, p Attribute, a ...interface{}) string {
 
 // Black is a convenient helper function to print with black foreground. A
 // newline is appended to format by default.
-func Black(format string, a ...interface{}) { colourPrint(format, FgBlack, a...) }
+func Black(format string, a ...interface{}) { colourPrint(GetOutput(), format, FgBlack, a...) }
 
 // Red is a convenient helper function to print with red foreground. A
 // newline is appended to format by default.
-func Red(format string, a ...interface{}) { colourPrint(format, FgRed, a...) }
+func Red(format string, a ...interface{}) { colourPrint(GetOutput(), format, FgRed, a...) }
 
 // Green is a convenient helper function to print with green foreground. A
 // newline is appended to format by default.
-func Green(format string, a ...interface{}) { colourPrint(format, FgGreen, a...) }
+func Green(format string, a ...interface{}) { colourPrint(GetOutput(), format, FgGreen, a...) }
 
 // Yellow is a convenient helper function to print with yellow foreground.
 // A newline is appended to format by default.
-func Yellow(format string, a ...interface{}) { colourPrint(format, FgYellow, a...) }
+func Yellow(format string, a ...interface{}) { colourPrint(GetOutput(), format, FgYellow, a...) }
 
 // Blue is a convenient helper function to print with blue foreground. A
 // newline is appended to format by default.
-func Blue(format string, a ...interface{}) { colourPrint(format, FgBlue, a...) }
+func Blue(format string, a ...interface{}) { colourPrint(GetOutput(), format, FgBlue, a...) }
 
 // Magenta is a convenient helper function to print with magenta foreground.
 // A newline is appended to format by default.
-func Magenta(format string, a ...interface{}) { colourPrint(format, FgMagenta, a...) }
+func Magenta(format string, a ...interface{}) { colourPrint(GetOutput(), format, FgMagenta, a...) }
 
 // Cyan is a convenient helper function to print with cyan foreground. A
 // newline is appended to format by default.
-func Cyan(format string, a ...interface{}) { colourPrint(format, FgCyan, a...) }
+func Cyan(format string, a ...interface{}) { colourPrint(GetOutput(), format, FgCyan, a...) }
 
 // White is a convenient helper function to print with white foreground. A
 // newline is appended to format by default.
-func White(format string, a ...interface{}) { colourPrint(format, FgWhite, a...) }
+func White(format string, a ...interface{}) { colourPrint(GetOutput(), format, FgWhite, a...) }
+
+// BlackError is like Black but prints to Error, standard error by default. A
+// newline is appended to format by default.
+func BlackError(format string, a ...interface{}) { colourPrint(GetError(), format, FgBlack, a...) }
+
+// RedError is like Red but prints to Error, standard error by default. A
+// newline is appended to format by default.
+func RedError(format string, a ...interface{}) { colourPrint(GetError(), format, FgRed, a...) }
+
+// GreenError is like Green but prints to Error, standard error by default. A
+// newline is appended to format by default.
+func GreenError(format string, a ...interface{}) { colourPrint(GetError(), format, FgGreen, a...) }
+
+// YellowError is like Yellow but prints to Error, standard error by default. A
+// newline is appended to format by default.
+func YellowError(format string, a ...interface{}) { colourPrint(GetError(), format, FgYellow, a...) }
+
+// BlueError is like Blue but prints to Error, standard error by default. A
+// newline is appended to format by default.
+func BlueError(format string, a ...interface{}) { colourPrint(GetError(), format, FgBlue, a...) }
+
+// MagentaError is like Magenta but prints to Error, standard error by default. A
+// newline is appended to format by default.
+func MagentaError(format string, a ...interface{}) { colourPrint(GetError(), format, FgMagenta, a...) }
+
+// CyanError is like Cyan but prints to Error, standard error by default. A
+// newline is appended to format by default.
+func CyanError(format string, a ...interface{}) { colourPrint(GetError(), format, FgCyan, a...) }
+
+// WhiteError is like White but prints to Error, standard error by default. A
+// newline is appended to format by default.
+func WhiteError(format string, a ...interface{}) { colourPrint(GetError(), format, FgWhite, a...) }
 
 // BlackString is a convenient helper function to return a string with black
 // foreground.
@@ -530,35 +1163,35 @@ func WhiteString(format string, a ...interface{}) string { return colourString(f
 
 // HiBlack is a convenient helper function to print with hi-intensity black foreground. A
 // newline is appended to format by default.
-func HiBlack(format string, a ...interface{}) { colourPrint(format, FgHiBlack, a...) }
+func HiBlack(format string, a ...interface{}) { colourPrint(GetOutput(), format, FgHiBlack, a...) }
 
 // HiRed is a convenient helper function to print with hi-intensity red foreground. A
 // newline is appended to format by default.
-func HiRed(format string, a ...interface{}) { colourPrint(format, FgHiRed, a...) }
+func HiRed(format string, a ...interface{}) { colourPrint(GetOutput(), format, FgHiRed, a...) }
 
 // HiGreen is a convenient helper function to print with hi-intensity green foreground. A
 // newline is appended to format by default.
-func HiGreen(format string, a ...interface{}) { colourPrint(format, FgHiGreen, a...) }
+func HiGreen(format string, a ...interface{}) { colourPrint(GetOutput(), format, FgHiGreen, a...) }
 
 // HiYellow is a convenient helper function to print with hi-intensity yellow foreground.
 // A newline is appended to format by default.
-func HiYellow(format string, a ...interface{}) { colourPrint(format, FgHiYellow, a...) }
+func HiYellow(format string, a ...interface{}) { colourPrint(GetOutput(), format, FgHiYellow, a...) }
 
 // HiBlue is a convenient helper function to print with hi-intensity blue foreground. A
 // newline is appended to format by default.
-func HiBlue(format string, a ...interface{}) { colourPrint(format, FgHiBlue, a...) }
+func HiBlue(format string, a ...interface{}) { colourPrint(GetOutput(), format, FgHiBlue, a...) }
 
 // HiMagenta is a convenient helper function to print with hi-intensity magenta foreground.
 // A newline is appended to format by default.
-func HiMagenta(format string, a ...interface{}) { colourPrint(format, FgHiMagenta, a...) }
+func HiMagenta(format string, a ...interface{}) { colourPrint(GetOutput(), format, FgHiMagenta, a...) }
 
 // HiCyan is a convenient helper function to print with hi-intensity cyan foreground. A
 // newline is appended to format by default.
-func HiCyan(format string, a ...interface{}) { colourPrint(format, FgHiCyan, a...) }
+func HiCyan(format string, a ...interface{}) { colourPrint(GetOutput(), format, FgHiCyan, a...) }
 
 // HiWhite is a convenient helper function to print with hi-intensity white foreground. A
 // newline is appended to format by default.
-func HiWhite(format string, a ...interface{}) { colourPrint(format, FgHiWhite, a...) }
+func HiWhite(format string, a ...interface{}) { colourPrint(GetOutput(), format, FgHiWhite, a...) }
 
 // HiBlackString is a convenient helper function to return a string with hi-intensity black
 // foreground.
@@ -3,12 +3,24 @@ package colour
 import (
 	"bytes"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"reflect"
+	"runtime"
+	"sync"
 	"testing"
 
 	"github.com/mattn/go-colorable"
 )
 
+// TestMain fixes the colour level, which would otherwise be detected from the
+// environment running the tests, turning colour off where TERM is not set.
+func TestMain(m *testing.M) {
+	SetLevel(Level16)
+	os.Exit(m.Run())
+}
+
 // Testing colours is kinda different. First we test for given colours and their
 // escaped formatted results. Next we create some visual tests to be tested.
 // Each visual test includes the colour name to be compared.
@@ -38,6 +50,7 @@ func TestColour(t *testing.T) {
 		{text: "hmagent", code: FgHiMagenta},
 		{text: "hcyan", code: FgHiCyan},
 		{text: "hwhite", code: FgHiWhite},
+		{text: "overline", code: Overline},
 	}
 
 	for _, c := range testColours {
@@ -101,6 +114,192 @@ func TestColourEquals(t *testing.T) {
 	if fgblack1.Equals(fgblackbgred) {
 		t.Error("Fg black equals fg black bg red")
 	}
+
+	tests := []struct {
+		a, b *Colour
+		want bool
+	}{
+		{New(FgRed, FgRed), New(FgRed), true},
+		{New(FgRed, Bold), New(Bold, FgRed), true},
+		{New(FgRed, Bold, Bold), New(Bold, FgRed, FgRed), true},
+		{New(FgRed, FgRed, Bold), New(FgRed, Bold, Underline), false},
+		{New(FgRed, FgRed), New(FgRed, Bold), false},
+		{New(), New(), true},
+		{New(), New(Reset), false},
+	}
+
+	for i, tt := range tests {
+		if got := tt.a.Equals(tt.b); got != tt.want {
+			t.Errorf("[%d] want: %t, got: %t", i, tt.want, got)
+		}
+		if got := tt.b.Equals(tt.a); got != tt.want {
+			t.Errorf("[%d] reversed want: %t, got: %t", i, tt.want, got)
+		}
+	}
+}
+
+func TestNewIf(t *testing.T) {
+	NoColour = false
+
+	tests := []struct {
+		c    *Colour
+		want string
+	}{
+		{NewIf(true, FgRed), "\x1b[31mx\x1b[0m"},
+		{NewIf(false, FgRed), "x"},
+		{NewIf(false, FgRed, Bold), "x"},
+	}
+
+	for i, tt := range tests {
+		if got := tt.c.Sprintf("%s", "x"); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+	}
+
+	if c := NewIf(false, FgRed); !c.Equals(New(FgRed)) {
+		t.Error("disabled colour lost its attributes")
+	}
+}
+
+func TestColourClone(t *testing.T) {
+	NoColour = false
+
+	base := New(FgRed, Bold)
+	base.DisableColour()
+
+	// leave spare capacity so appending could share the backing array
+	base.params = append(make([]param, 0, 10), base.params...)
+
+	clone := base.Clone()
+	if !clone.Equals(base) {
+		t.Error("Clone is not equal to the original")
+	}
+
+	clone.Add(Underline)
+	clone.EnableColour()
+	base.Add(BgBlue)
+
+	if got, want := base.Sprint("x"), "x"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+	if got, want := clone.Sprint("x"), "\x1b[31;1;4mx\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+}
+
+func TestColourRemove(t *testing.T) {
+	defer SetLevel(GetLevel())
+	SetLevel(LevelTrueColour)
+
+	tests := []struct {
+		c    *Colour
+		want string
+	}{
+		{New(FgRed, Bold).Remove(Bold), "31"},
+		{New(Bold, FgRed, Bold).Remove(Bold), "31"},
+		{New(FgRed, Bold, Underline).Remove(Bold, Underline), "31"},
+		{New(FgRed).Remove(Bold), "31"},
+		{New(Bold).Remove(Bold), ""},
+		{New().Remove(Bold), ""},
+		// values inside extended colours are not attributes
+		{New(Bold).AddFg256(1).Remove(Bold), "38;5;1"},
+		{New(Bold).AddFg256(1).Remove(BlinkSlow), "1;38;5;1"},
+		{New(Bold).AddFg256(1).Remove(extendedFg), "1"},
+	}
+
+	for i, tt := range tests {
+		if got := tt.c.sequence(); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+	}
+}
+
+func TestSprintFuncCached(t *testing.T) {
+	NoColour = false
+
+	c := New(FgRed, Bold)
+	f := c.SprintFuncCached()
+	if got, want := f("a", 1), c.SprintFunc()("a", 1); got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+
+	c.DisableColour()
+	if got, want := f("a"), "a"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+}
+
+func BenchmarkSprintFunc(b *testing.B) {
+	NoColour = false
+	f := New(FgRed, Bold).SprintFunc()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		f("benchmark")
+	}
+}
+
+func BenchmarkSprintFuncCached(b *testing.B) {
+	NoColour = false
+	f := New(FgRed, Bold).SprintFuncCached()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		f("benchmark")
+	}
+}
+
+func BenchmarkSprintFuncNoColour(b *testing.B) {
+	c := New(FgRed, Bold)
+	c.DisableColour()
+	f := c.SprintFunc()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		f("benchmark")
+	}
+}
+
+func BenchmarkSprintStringFunc(b *testing.B) {
+	NoColour = false
+	f := New(FgRed, Bold).SprintStringFunc()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		f("benchmark")
+	}
+}
+
+func BenchmarkSprintStringFuncNoColour(b *testing.B) {
+	c := New(FgRed, Bold)
+	c.DisableColour()
+	f := c.SprintStringFunc()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		f("benchmark")
+	}
+}
+
+func TestSprintStringFunc(t *testing.T) {
+	NoColour = false
+	c := New(FgRed, Bold)
+	f := c.SprintStringFunc()
+
+	if got, want := f("a"), "\x1b[31;1ma\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+	if got, want := f("a"), c.SprintFunc()("a"); got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+
+	c.DisableColour()
+	if got, want := f("a"), "a"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+	if got, want := c.SprintFunc()("a"), "a"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
 }
 
 func TestNoColour(t *testing.T) {
@@ -157,6 +356,69 @@ func TestNoColour(t *testing.T) {
 
 }
 
+func TestDetectNoColour(t *testing.T) {
+	defer setenv("TERM", strPtr("xterm"))()
+	defer setenv("NO_COLOR", nil)()
+	defer setenv("FORCE_COLOR", nil)()
+
+	if detectNoColour(true) {
+		t.Error("Colour disabled for a terminal")
+	}
+	if !detectNoColour(false) {
+		t.Error("Colour enabled for a non-terminal")
+	}
+
+	os.Setenv("NO_COLOR", "1")
+	if !detectNoColour(true) {
+		t.Error("Colour enabled for a terminal with NO_COLOR set")
+	}
+
+	os.Setenv("NO_COLOR", "")
+	if detectNoColour(true) {
+		t.Error("Colour disabled for a terminal with NO_COLOR empty")
+	}
+
+	os.Unsetenv("NO_COLOR")
+	os.Setenv("TERM", "dumb")
+	if !detectNoColour(true) {
+		t.Error("Colour enabled for a dumb terminal")
+	}
+}
+
+func TestForceColour(t *testing.T) {
+	defer setenv("TERM", strPtr("xterm"))()
+	defer setenv("NO_COLOR", nil)()
+	defer setenv("FORCE_COLOR", nil)()
+
+	tests := []struct {
+		force, noColour *string
+		isTerminal      bool
+		want            bool
+	}{
+		{strPtr("1"), nil, false, false},
+		{strPtr("3"), nil, false, false},
+		{strPtr(""), nil, false, false},
+		{strPtr("true"), nil, false, false},
+		{strPtr("1"), strPtr("1"), false, false},
+		{strPtr("0"), nil, true, true},
+		{strPtr("false"), nil, true, true},
+		{strPtr("0"), strPtr("1"), true, true},
+		{nil, strPtr("1"), true, true},
+	}
+
+	for i, tt := range tests {
+		restoreForce := setenv("FORCE_COLOR", tt.force)
+		restoreNoColour := setenv("NO_COLOR", tt.noColour)
+
+		if got := detectNoColour(tt.isTerminal); got != tt.want {
+			t.Errorf("[%d] want: %v, got: %v", i, tt.want, got)
+		}
+
+		restoreNoColour()
+		restoreForce()
+	}
+}
+
 func TestColourVisual(t *testing.T) {
 	// First Visual Test
 	Output = colorable.NewColorableStdout()
@@ -340,3 +602,620 @@ func TestNoFormatString(t *testing.T) {
 		}
 	}
 }
+
+func TestSetOutput(t *testing.T) {
+	NoColour = false
+	oldOut, oldErr := GetOutput(), GetError()
+	defer func() {
+		SetOutput(oldOut)
+		SetError(oldErr)
+	}()
+
+	out, errOut := new(bytes.Buffer), new(bytes.Buffer)
+	SetOutput(out)
+	SetError(errOut)
+
+	if GetOutput() != out || GetError() != errOut {
+		t.Fatal("Writers not set")
+	}
+
+	New(FgRed).Print("a")
+	New(FgRed).Printf("%s", "b")
+	New(FgRed).Println("c")
+	Set(FgBlue)
+	Unset()
+	Alert("d")
+
+	if got, want := out.String(), "\x1b[31ma\x1b[0m\x1b[31mb\x1b[0m\x1b[31mc\n\x1b[0m\x1b[34m\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+	if got, want := errOut.String(), "\x1b[31md\n\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+}
+
+func TestSetf(t *testing.T) {
+	NoColour = false
+
+	var buf bytes.Buffer
+	restore := Setf(&buf, FgRed, Bold)
+	buf.WriteString("a")
+	inner := Setf(&buf, FgBlue)
+	buf.WriteString("b")
+	inner()
+	restore()
+
+	if got, want := buf.String(), "\x1b[31;1ma\x1b[34mb\x1b[0m\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+
+	NoColour = true
+	defer func() { NoColour = false }()
+
+	buf.Reset()
+	restore = Setf(&buf, FgRed)
+	restore()
+	if got := buf.String(); got != "" {
+		t.Errorf("want: %q, got: %q", "", got)
+	}
+}
+
+func TestAttributes(t *testing.T) {
+	c := New(FgRed, Bold)
+	attrs := c.Attributes()
+	if !reflect.DeepEqual(attrs, []Attribute{FgRed, Bold}) {
+		t.Errorf("unexpected attributes %v", attrs)
+	}
+
+	attrs[0] = FgBlue
+	if got := c.Attributes()[0]; got != FgRed {
+		t.Errorf("want: %v, got: %v", FgRed, got)
+	}
+
+	if got := New().Attributes(); got == nil || len(got) != 0 {
+		t.Errorf("want empty attributes, got: %v", got)
+	}
+}
+
+func TestBoundFprintFuncs(t *testing.T) {
+	NoColour = false
+
+	var buf bytes.Buffer
+	c := New(FgRed)
+	c.BoundFprintFunc(&buf)("a", 1)
+	c.BoundFprintfFunc(&buf)("%d", 2)
+	c.BoundFprintlnFunc(&buf)("b")
+
+	want := "\x1b[31ma1\x1b[0m\x1b[31m2\x1b[0m\x1b[31mb\n\x1b[0m"
+	if got := buf.String(); got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+}
+
+func TestIsEmpty(t *testing.T) {
+	tests := []struct {
+		c    *Colour
+		want bool
+	}{
+		{New(), true},
+		{Plain, true},
+		{NewIf(false), true},
+		{New(FgRed), false},
+		{NewIf(false, FgRed), false},
+		{New(Bold).Remove(Bold), true},
+		{NewFg256(1), false},
+	}
+
+	for i, tt := range tests {
+		if got := tt.c.IsEmpty(); got != tt.want {
+			t.Errorf("[%d] want: %v, got: %v", i, tt.want, got)
+		}
+	}
+}
+
+func TestSetWriter(t *testing.T) {
+	NoColour = false
+
+	var buf bytes.Buffer
+	SetWriter(&buf, FgGreen)
+	buf.WriteString("a")
+	UnsetWriter(&buf)
+
+	if got, want := buf.String(), "\x1b[32ma\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+
+	NoColour = true
+	defer func() { NoColour = false }()
+
+	buf.Reset()
+	SetWriter(&buf, FgGreen)
+	UnsetWriter(&buf)
+	if got := buf.String(); got != "" {
+		t.Errorf("want: %q, got: %q", "", got)
+	}
+}
+
+func TestWrapPrefixSuffix(t *testing.T) {
+	NoColour = false
+
+	c := New(FgRed, Bold)
+	prefix, suffix := c.Wrap()
+	if got, want := prefix+"x"+suffix, c.Sprint("x"); got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+	if prefix != "\x1b[31;1m" || suffix != "\x1b[0m" {
+		t.Errorf("unexpected prefix %q and suffix %q", prefix, suffix)
+	}
+
+	c.DisableColour()
+	if prefix, suffix := c.Wrap(); prefix != "" || suffix != "" {
+		t.Errorf("want empty, got: %q, %q", prefix, suffix)
+	}
+}
+
+func TestColoursCache(t *testing.T) {
+	ClearCache()
+	defer ClearCache()
+
+	if getCachedColour(FgRed) != getCachedColour(FgRed) {
+		t.Error("colour not cached")
+	}
+
+	for i := 0; i < 2*maxCachedColours; i++ {
+		getCachedColour(Attribute(i))
+	}
+	if n := len(coloursCache); n != maxCachedColours {
+		t.Errorf("want: %d cached colours, got: %d", maxCachedColours, n)
+	}
+
+	ClearCache()
+	if n := len(coloursCache); n != 0 {
+		t.Errorf("want: empty cache, got: %d", n)
+	}
+}
+
+func TestHelpersFollowNoColour(t *testing.T) {
+	oldOut := GetOutput()
+	defer SetOutput(oldOut)
+	defer SetNoColour(false)
+
+	rb := new(bytes.Buffer)
+	SetOutput(rb)
+
+	SetNoColour(true)
+	Red("a")
+	plain := RedString("b")
+
+	SetNoColour(false)
+	Red("a")
+	coloured := RedString("b")
+
+	if got, want := rb.String(), "a\n\x1b[31ma\n\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+	if plain != "b" || coloured != "\x1b[31mb\x1b[0m" {
+		t.Errorf("want: %q and %q, got: %q and %q", "b", "\x1b[31mb\x1b[0m", plain, coloured)
+	}
+}
+
+func TestSprintLines(t *testing.T) {
+	NoColour = false
+	red := New(FgRed)
+
+	tests := []struct {
+		s, want string
+	}{
+		{"", ""},
+		{"one", "\x1b[31mone\x1b[0m"},
+		{"one\ntwo", "\x1b[31mone\x1b[0m\n\x1b[31mtwo\x1b[0m"},
+		{"one\n", "\x1b[31mone\x1b[0m\n"},
+		{"one\n\ntwo\n", "\x1b[31mone\x1b[0m\n\n\x1b[31mtwo\x1b[0m\n"},
+	}
+
+	for i, tt := range tests {
+		if got := red.SprintLines(tt.s); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+	}
+
+	red.DisableColour()
+	if got := red.SprintLines("a\nb\n"); got != "a\nb\n" {
+		t.Errorf("want: %q, got: %q", "a\nb\n", got)
+	}
+}
+
+func TestColourMerge(t *testing.T) {
+	tests := []struct {
+		a, b *Colour
+		want []Attribute
+	}{
+		{New(Bold), New(FgRed), []Attribute{Bold, FgRed}},
+		{New(FgRed, Bold), New(FgBlue), []Attribute{Bold, FgBlue}},
+		{New(Bold, Underline), New(Underline, Italic), []Attribute{Bold, Underline, Italic}},
+		{New(Bold, Bold), New(), []Attribute{Bold}},
+		{New(FgRed, BgWhite), New(BgHiBlack), []Attribute{FgRed, BgHiBlack}},
+		{New(FgRed).AddBg256(3), NewBg256(4), []Attribute{FgRed, 48, 5, 4}},
+		{NewFg256(1), New(FgHiRed, Bold), []Attribute{FgHiRed, Bold}},
+		{New(FgRed, Bold), New(Bold, FgRed), []Attribute{FgRed, Bold}},
+		{New(Underline).UnderlineColour256(1), New().UnderlineColour256(2), []Attribute{Underline, 58, 5, 2}},
+		{New(FgRed), New(39), []Attribute{39}},
+	}
+
+	for i, tt := range tests {
+		a := tt.a.Clone()
+		got := tt.a.Merge(tt.b)
+		if got := got.Attributes(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("[%d] want: %v, got: %v", i, tt.want, got)
+		}
+		if !tt.a.Equals(a) {
+			t.Errorf("[%d] receiver modified", i)
+		}
+	}
+
+	disabled := New(Bold)
+	disabled.DisableColour()
+	if got := disabled.Merge(New(FgRed)).Sprint("x"); got != "x" {
+		t.Errorf("want: %q, got: %q", "x", got)
+	}
+}
+
+func TestPlain(t *testing.T) {
+	defer func() { NoColour = false }()
+
+	for _, noColour := range []bool{false, true} {
+		NoColour = noColour
+		if got := Plain.Sprint("x"); got != "x" {
+			t.Errorf("NoColour %t want: %q, got: %q", noColour, "x", got)
+		}
+		if !Plain.IsNoColour() {
+			t.Errorf("NoColour %t: Plain is not disabled", noColour)
+		}
+	}
+}
+
+func TestIsNoColour(t *testing.T) {
+	defer func() { NoColour = false }()
+
+	tests := []struct {
+		global   bool
+		disable  *bool
+		expected bool
+	}{
+		{false, nil, false},
+		{true, nil, true},
+		{false, boolPtr(true), true},
+		{true, boolPtr(false), false},
+	}
+
+	for i, tt := range tests {
+		NoColour = tt.global
+		c := New(FgRed)
+		if tt.disable != nil {
+			if *tt.disable {
+				c.DisableColour()
+			} else {
+				c.EnableColour()
+			}
+		}
+		if got := c.IsNoColour(); got != tt.expected {
+			t.Errorf("[%d] want: %t, got: %t", i, tt.expected, got)
+		}
+	}
+}
+
+func TestErrorHelpers(t *testing.T) {
+	NoColour = false
+	oldOut, oldErr := GetOutput(), GetError()
+	defer func() {
+		SetOutput(oldOut)
+		SetError(oldErr)
+	}()
+
+	out, errOut := new(bytes.Buffer), new(bytes.Buffer)
+	SetOutput(out)
+	SetError(errOut)
+
+	RedError("failed: %s", "x")
+	GreenError("done")
+	Red("stdout")
+
+	if got, want := errOut.String(), "\x1b[31mfailed: x\n\x1b[0m\x1b[32mdone\n\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+	if got, want := out.String(), "\x1b[31mstdout\n\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+}
+
+func TestReconfigureFromOutput(t *testing.T) {
+	oldOut := GetOutput()
+	defer SetOutput(oldOut)
+	defer SetNoColour(false)
+	defer setenv("FORCE_COLOR", nil)()
+	defer setenv("NO_COLOR", nil)()
+
+	f, err := ioutil.TempFile("", "colour")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	// a file is not a terminal
+	SetNoColour(false)
+	SetOutput(f)
+	ReconfigureFromOutput()
+	if !GetNoColour() {
+		t.Error("colour enabled for a file")
+	}
+
+	// unless colour is forced
+	restore := setenv("FORCE_COLOR", strPtr("1"))
+	ReconfigureFromOutput()
+	restore()
+	if GetNoColour() {
+		t.Error("forced colour disabled")
+	}
+
+	// writers without a file descriptor are left alone
+	SetOutput(new(bytes.Buffer))
+	ReconfigureFromOutput()
+	if GetNoColour() {
+		t.Error("NoColour changed for a buffer")
+	}
+}
+
+func TestSprintReset(t *testing.T) {
+	NoColour = false
+	red := New(FgRed)
+	base := New(BgBlue)
+	plain := New(BgBlue)
+	plain.DisableColour()
+
+	tests := []struct {
+		got, want string
+	}{
+		{red.SprintReset(base, "x"), "\x1b[31mx\x1b[0m\x1b[44m"},
+		{red.SprintReset(nil, "x"), "\x1b[31mx\x1b[0m"},
+		{red.SprintReset(plain, "x"), "\x1b[31mx\x1b[0m"},
+		{New(Bold).SprintReset(base, "a", 1), "\x1b[1ma1\x1b[0m\x1b[44m"},
+	}
+
+	for i, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, tt.got)
+		}
+	}
+
+	red.DisableColour()
+	if got := red.SprintReset(base, "x"); got != "x" {
+		t.Errorf("want: %q, got: %q", "x", got)
+	}
+}
+
+func TestAutoColorable(t *testing.T) {
+	var buf bytes.Buffer
+	if autoColorable(&buf) != io.Writer(&buf) {
+		t.Error("non-file writer was wrapped")
+	}
+
+	if runtime.GOOS != "windows" && autoColorable(os.Stdout) != io.Writer(os.Stdout) {
+		t.Error("file was wrapped outside of Windows")
+	}
+}
+
+// Run with -race.
+func TestNoColourConcurrent(t *testing.T) {
+	defer SetNoColour(false)
+	oldOut := GetOutput()
+	defer SetOutput(oldOut)
+	SetOutput(ioutil.Discard)
+
+	c := New(FgRed)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			SetNoColour(i%2 == 0)
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		c.Sprint("x")
+		c.Print("x")
+		RedString("x")
+	}
+	<-done
+
+	SetNoColour(true)
+	if !GetNoColour() || !NoColour {
+		t.Error("NoColour not set")
+	}
+}
+
+type shortWriter struct {
+	limit int
+	buf   bytes.Buffer
+}
+
+func (w *shortWriter) Write(p []byte) (int, error) {
+	if len(p) > w.limit {
+		w.buf.Write(p[:w.limit])
+		return w.limit, io.ErrShortWrite
+	}
+	return w.buf.Write(p)
+}
+
+func TestFprintCounted(t *testing.T) {
+	NoColour = false
+
+	tests := []struct {
+		c        *Colour
+		limit    int
+		n, total int
+		err      error
+	}{
+		{New(FgRed), 100, 5, 14, nil},
+		{New(FgRed, Bold), 100, 5, 16, nil},
+		{NewIf(false, FgRed), 100, 5, 5, nil},
+		{New(FgRed), 7, 2, 7, io.ErrShortWrite},
+		{New(FgRed), 3, 0, 3, io.ErrShortWrite},
+		{New(FgRed), 12, 5, 12, io.ErrShortWrite},
+	}
+
+	for i, tt := range tests {
+		w := &shortWriter{limit: tt.limit}
+		n, total, err := tt.c.FprintCounted(w, "he", "llo")
+		if n != tt.n || total != tt.total || err != tt.err {
+			t.Errorf("[%d] want: %d, %d, %v, got: %d, %d, %v", i, tt.n, tt.total, tt.err, n, total, err)
+		}
+		if total != w.buf.Len() {
+			t.Errorf("[%d] reported %d bytes, wrote %d", i, total, w.buf.Len())
+		}
+	}
+}
+
+func TestEmptyColour(t *testing.T) {
+	NoColour = false
+
+	c := New()
+	if got := c.Sprint("x"); got != "x" {
+		t.Errorf("want: %q, got: %q", "x", got)
+	}
+	if got := c.SprintFuncCached()("x"); got != "x" {
+		t.Errorf("want: %q, got: %q", "x", got)
+	}
+	if prefix, suffix := c.Wrap(); prefix != "" || suffix != "" {
+		t.Errorf("want empty, got: %q, %q", prefix, suffix)
+	}
+
+	var buf bytes.Buffer
+	c.Fprint(&buf, "x")
+	if got := buf.String(); got != "x" {
+		t.Errorf("want: %q, got: %q", "x", got)
+	}
+
+	oldOut := GetOutput()
+	defer SetOutput(oldOut)
+	buf.Reset()
+	SetOutput(&buf)
+
+	c.Print("x")
+	c.Set()
+	c.SetPartial()()
+	if got := buf.String(); got != "x" {
+		t.Errorf("want: %q, got: %q", "x", got)
+	}
+}
+
+type stringer string
+
+func (s stringer) String() string { return string(s) }
+
+func TestSprintBytes(t *testing.T) {
+	NoColour = false
+
+	red := New(FgRed)
+	want := red.Sprint("text")
+	if got := red.SprintBytes([]byte("text")); got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+	if got := red.Sprint(stringer("text")); got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+	if got, want := red.Sprint([]byte("ab")), "\x1b[31m[97 98]\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+}
+
+func TestAlwaysReset(t *testing.T) {
+	NoColour = false
+
+	c := New()
+	c.AlwaysReset(true)
+	if got, want := c.Sprint("x"), "x\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+
+	var buf bytes.Buffer
+	c.Fprint(&buf, "x")
+	if got, want := buf.String(), "x\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+
+	if got, want := c.Clone().SprintFuncCached()("x"), "x\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+
+	red := New(FgRed)
+	red.AlwaysReset(true)
+	if got, want := red.Sprint("x"), "\x1b[31mx\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+
+	c.DisableColour()
+	if got := c.Sprint("x"); got != "x" {
+		t.Errorf("want: %q, got: %q", "x", got)
+	}
+
+	c.AlwaysReset(false)
+	c.EnableColour()
+	if got := c.Sprint("x"); got != "x" {
+		t.Errorf("want: %q, got: %q", "x", got)
+	}
+}
+
+func TestColourString(t *testing.T) {
+	defer SetLevel(GetLevel())
+	SetLevel(Level16)
+
+	tests := []struct {
+		c    *Colour
+		want string
+	}{
+		{New(Bold, FgRed), "Colour(1;31)"},
+		{New(), "Colour(<none>)"},
+		{RGB(1, 2, 3).Add(CurlyUnderline), "Colour(38;2;1;2;3;4:3)"},
+		{NewIf(false, FgBlue), "Colour(34)"},
+	}
+
+	for i, tt := range tests {
+		if got := fmt.Sprint(tt.c); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+	}
+}
+
+func TestColourConcurrentChange(t *testing.T) {
+	NoColour = false
+	defer SetLevel(GetLevel())
+	SetLevel(Level16)
+
+	c := New(FgRed)
+	sprint := c.SprintFunc()
+	valid := map[string]bool{
+		"\x1b[31mfoo\x1b[0m":   true,
+		"\x1b[31;1mfoo\x1b[0m": true,
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			c.Add(Bold)
+			c.Remove(Bold)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			if got := sprint("foo"); !valid[got] {
+				t.Errorf("[%d] got: %q", i, got)
+				return
+			}
+		}
+	}()
+	wg.Wait()
+}
@@ -0,0 +1,25 @@
+//go:build !windows
+// +build !windows
+
+package colour
+
+import "io"
+
+// UseNativeWindows replaces Output and Error with writers that drive the
+// legacy Windows console API directly. It is a no-op on other platforms.
+func UseNativeWindows() error {
+	return nil
+}
+
+// EnableVirtualTerminalProcessing enables native escape sequence support of
+// the Windows console. It is a no-op on other platforms, where terminals
+// support escape sequences anyway.
+func EnableVirtualTerminalProcessing() (disable func(), err error) {
+	return func() {}, nil
+}
+
+// autoColorable returns w unchanged, escape sequences work as is on other
+// platforms.
+func autoColorable(w io.Writer) io.Writer {
+	return w
+}
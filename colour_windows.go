@@ -0,0 +1,120 @@
+//go:build windows
+// +build windows
+
+package colour
+
+import (
+	"io"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32                       = syscall.NewLazyDLL("kernel32.dll")
+	procGetConsoleScreenBufferInfo = kernel32.NewProc("GetConsoleScreenBufferInfo")
+	procSetConsoleTextAttribute    = kernel32.NewProc("SetConsoleTextAttribute")
+	procGetConsoleMode             = kernel32.NewProc("GetConsoleMode")
+	procSetConsoleMode             = kernel32.NewProc("SetConsoleMode")
+)
+
+// enableVirtualTerminalProcessing is the console mode flag making the console
+// interpret escape sequences itself.
+const enableVirtualTerminalProcessing = 0x0004
+
+type coord struct {
+	x, y int16
+}
+
+type smallRect struct {
+	left, top, right, bottom int16
+}
+
+type consoleScreenBufferInfo struct {
+	size              coord
+	cursorPosition    coord
+	attributes        uint16
+	window            smallRect
+	maximumWindowSize coord
+}
+
+// UseNativeWindows replaces Output and Error with writers that drive the
+// legacy console API directly rather than through go-colorable. Use it on
+// consoles without VT support where colorable renders combinations of bold
+// and background colours incorrectly. An error is returned if stdout or
+// stderr is not a console, in which case nothing is changed.
+func UseNativeWindows() error {
+	out, err := newNativeConsole(os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	errOut, err := newNativeConsole(os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	outputMu.Lock()
+	Output, Error = out, errOut
+	outputMu.Unlock()
+
+	return nil
+}
+
+func newNativeConsole(f *os.File) (io.Writer, error) {
+	h := f.Fd()
+
+	var info consoleScreenBufferInfo
+	r, _, err := procGetConsoleScreenBufferInfo.Call(h, uintptr(unsafe.Pointer(&info)))
+	if r == 0 {
+		return nil, err
+	}
+
+	return newConsoleWriter(f, info.attributes, func(attr uint16) error {
+		r, _, err := procSetConsoleTextAttribute.Call(h, uintptr(attr))
+		if r == 0 {
+			return err
+		}
+		return nil
+	}), nil
+}
+
+// autoColorable wraps w with colorable if it is a file, so the escape
+// sequences written by the Fprint methods are translated for consoles
+// without VT support.
+func autoColorable(w io.Writer) io.Writer {
+	return colorableFile(w)
+}
+
+// EnableVirtualTerminalProcessing switches the console behind stdout to
+// interpret escape sequences natively, as supported since Windows 10, and
+// sets Output to os.Stdout directly so they are no longer translated by
+// go-colorable. The returned function restores the previous console mode and
+// Output. An error is returned if stdout is not a console or the mode is not
+// supported, in which case nothing is changed.
+func EnableVirtualTerminalProcessing() (disable func(), err error) {
+	h := os.Stdout.Fd()
+
+	var mode uint32
+	r, _, err := procGetConsoleMode.Call(h, uintptr(unsafe.Pointer(&mode)))
+	if r == 0 {
+		return nil, err
+	}
+	r, _, err = procSetConsoleMode.Call(h, uintptr(mode|enableVirtualTerminalProcessing))
+	if r == 0 {
+		return nil, err
+	}
+
+	outputMu.Lock()
+	old := Output
+	Output = os.Stdout
+	outputMu.Unlock()
+
+	return func() {
+		procSetConsoleMode.Call(h, uintptr(mode))
+
+		outputMu.Lock()
+		Output = old
+		outputMu.Unlock()
+	}, nil
+}
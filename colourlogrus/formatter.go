@@ -0,0 +1,151 @@
+// Package colourlogrus provides a logrus formatter colouring log entries
+// with the colour package.
+//
+// It is a separate module so that the colour package does not depend on
+// logrus:
+//
+//	logrus.SetFormatter(colourlogrus.NewFormatter(&colourlogrus.Options{
+//		KeyColour: colour.New(colour.FgBlue),
+//	}))
+//
+// The module requires a published version of the colour package. To build it
+// against the working copy instead, create a workspace in the repository
+// root, which git ignores:
+//
+//	go work init . ./colourlogrus
+package colourlogrus
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/felix/colour"
+	"github.com/sirupsen/logrus"
+)
+
+// Options configures a formatter returned by NewFormatter. The zero value
+// gives the default colours.
+type Options struct {
+	// LevelColours maps levels to the colour their name is shown in. Levels
+	// missing from the map are left plain. The defaults of
+	// DefaultLevelColours are used if nil.
+	LevelColours map[logrus.Level]*colour.Colour
+
+	// TimeColour, KeyColour and ValueColour colour the timestamp and the
+	// keys and values of fields. They are left plain if nil.
+	TimeColour  *colour.Colour
+	KeyColour   *colour.Colour
+	ValueColour *colour.Colour
+
+	// TimestampFormat is the layout of timestamps, "15:04:05.000" if empty.
+	TimestampFormat string
+
+	// DisableTimestamp leaves out the timestamp.
+	DisableTimestamp bool
+
+	// ForceColour colours the output even if the logger does not write to a
+	// terminal. The global colour.NoColour setting still applies.
+	ForceColour bool
+}
+
+// DefaultLevelColours are the level colours used by NewFormatter unless set
+// in Options.
+var DefaultLevelColours = map[logrus.Level]*colour.Colour{
+	logrus.TraceLevel: colour.New(colour.FgHiBlack),
+	logrus.DebugLevel: colour.New(colour.FgHiBlack),
+	logrus.InfoLevel:  colour.New(colour.FgCyan),
+	logrus.WarnLevel:  colour.New(colour.FgYellow),
+	logrus.ErrorLevel: colour.New(colour.FgRed, colour.Bold),
+	logrus.FatalLevel: colour.New(colour.FgRed, colour.Bold),
+	logrus.PanicLevel: colour.New(colour.FgRed, colour.Bold),
+}
+
+// levelWidth is the width level names are padded to, that of "WARNING".
+const levelWidth = 7
+
+// Formatter is a logrus.Formatter writing one line per entry with the level
+// coloured, followed by the message and the fields as key=value pairs sorted
+// by key:
+//
+//	15:04:05.000 INFO    listening addr=:8080
+type Formatter struct {
+	opts Options
+}
+
+// NewFormatter returns a formatter with the given options, which may be nil
+// for the defaults. An entry is coloured if the output of its logger is a
+// terminal according to colour.SupportsColour, or if forced with
+// opts.ForceColour, and colour is not disabled globally.
+func NewFormatter(opts *Options) *Formatter {
+	f := &Formatter{}
+	if opts != nil {
+		f.opts = *opts
+	}
+	if f.opts.LevelColours == nil {
+		f.opts.LevelColours = DefaultLevelColours
+	}
+	if f.opts.TimestampFormat == "" {
+		f.opts.TimestampFormat = "15:04:05.000"
+	}
+
+	return f
+}
+
+// Format renders e as a single line.
+func (f *Formatter) Format(e *logrus.Entry) ([]byte, error) {
+	b := e.Buffer
+	if b == nil {
+		b = new(bytes.Buffer)
+	}
+
+	coloured := f.opts.ForceColour || (e.Logger != nil && colour.SupportsColour(e.Logger.Out))
+	paint := func(c *colour.Colour, s string) string {
+		if !coloured || c == nil {
+			return colour.Plain.Sprint(s)
+		}
+		return c.Sprint(s)
+	}
+
+	if !f.opts.DisableTimestamp && !e.Time.IsZero() {
+		b.WriteString(paint(f.opts.TimeColour, e.Time.Format(f.opts.TimestampFormat)))
+		b.WriteByte(' ')
+	}
+
+	name := strings.ToUpper(e.Level.String())
+	b.WriteString(paint(f.opts.LevelColours[e.Level], name))
+	if n := levelWidth - len(name); n > 0 {
+		b.WriteString(strings.Repeat(" ", n))
+	}
+	b.WriteByte(' ')
+	b.WriteString(paint(nil, e.Message))
+
+	keyColour, valColour := f.opts.KeyColour, f.opts.ValueColour
+	if !coloured {
+		keyColour, valColour = nil, nil
+	}
+
+	keys := make([]string, 0, len(e.Data))
+	for k := range e.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		b.WriteByte(' ')
+		b.WriteString(colour.KV(k, fieldValue(e.Data[k]), keyColour, valColour))
+	}
+	b.WriteByte('\n')
+
+	return b.Bytes(), nil
+}
+
+// fieldValue returns the value of a field as shown in the output, times in
+// RFC 3339 format and anything else as formatted by fmt.
+func fieldValue(v interface{}) interface{} {
+	if t, ok := v.(time.Time); ok {
+		return t.Format(time.RFC3339Nano)
+	}
+
+	return v
+}
@@ -0,0 +1,69 @@
+package colourlogrus
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/felix/colour"
+	"github.com/sirupsen/logrus"
+)
+
+func TestFormatter(t *testing.T) {
+	colour.NoColour = false
+	defer colour.SetLevel(colour.GetLevel())
+	colour.SetLevel(colour.Level16)
+
+	f := NewFormatter(&Options{
+		ForceColour: true,
+		KeyColour:   colour.New(colour.FgBlue),
+		TimeColour:  colour.New(colour.Faint),
+	})
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 6000000, time.UTC)
+	tests := []struct {
+		level  logrus.Level
+		msg    string
+		fields logrus.Fields
+		want   string
+	}{
+		{logrus.InfoLevel, "hello", nil, "\x1b[2m03:04:05.006\x1b[0m \x1b[36mINFO\x1b[0m    hello\n"},
+		{logrus.ErrorLevel, "failed", logrus.Fields{"err": errors.New("no such file"), "a": 1}, "\x1b[2m03:04:05.006\x1b[0m \x1b[31;1mERROR\x1b[0m   failed \x1b[34ma\x1b[0m=1 \x1b[34merr\x1b[0m=\"no such file\"\n"},
+		{logrus.WarnLevel, "w", nil, "\x1b[2m03:04:05.006\x1b[0m \x1b[33mWARNING\x1b[0m w\n"},
+	}
+
+	for i, tt := range tests {
+		e := &logrus.Entry{Time: now, Level: tt.level, Message: tt.msg, Data: tt.fields}
+		b, err := f.Format(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(b); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+	}
+}
+
+func TestFormatterPlain(t *testing.T) {
+	if v, ok := os.LookupEnv("FORCE_COLOR"); ok {
+		os.Unsetenv("FORCE_COLOR")
+		defer os.Setenv("FORCE_COLOR", v)
+	}
+	colour.NoColour = false
+
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.Out = &buf
+	logger.Formatter = NewFormatter(&Options{
+		DisableTimestamp: true,
+		KeyColour:        colour.New(colour.FgBlue),
+	})
+
+	logger.WithField("id", 7).Info("msg")
+
+	if want, got := "INFO    msg id=7\n", buf.String(); got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+}
@@ -0,0 +1,8 @@
+module github.com/felix/colour/colourlogrus
+
+go 1.13
+
+require (
+	github.com/felix/colour v0.0.0-20261016121256-b11151902e3a
+	github.com/sirupsen/logrus v1.9.4
+)
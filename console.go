@@ -0,0 +1,203 @@
+package colour
+
+import (
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Character attributes understood by the legacy Windows console API.
+const (
+	consoleFgBlue uint16 = 1 << iota
+	consoleFgGreen
+	consoleFgRed
+	consoleFgIntensity
+	consoleBgBlue
+	consoleBgGreen
+	consoleBgRed
+	consoleBgIntensity
+
+	consoleFgMask = consoleFgBlue | consoleFgGreen | consoleFgRed | consoleFgIntensity
+	consoleBgMask = consoleBgBlue | consoleBgGreen | consoleBgRed | consoleBgIntensity
+)
+
+// consoleState tracks the SGR state of a legacy console so that it can be
+// translated into character attributes. Bold is kept apart from the colours
+// as the console has no bold and instead intensifies the foreground, which
+// must hold no matter in which order the parameters arrive.
+type consoleState struct {
+	def     uint16 // attributes in effect before any SGR sequence
+	fg, bg  uint16 // colours in the low nibble, without bold applied
+	bold    bool
+	reverse bool
+}
+
+func newConsoleState(def uint16) *consoleState {
+	s := &consoleState{def: def}
+	s.reset()
+	return s
+}
+
+func (s *consoleState) reset() {
+	s.fg = s.def & consoleFgMask
+	s.bg = (s.def & consoleBgMask) >> 4
+	s.bold = false
+	s.reverse = false
+}
+
+// apply updates the state with the given SGR parameters.
+func (s *consoleState) apply(params []int) {
+	for i := 0; i < len(params); i++ {
+		n := params[i]
+		switch {
+		case n == 0:
+			s.reset()
+		case n == 1:
+			s.bold = true
+		case n == 22:
+			s.bold = false
+		case n == 7:
+			s.reverse = true
+		case n == 27:
+			s.reverse = false
+		case n >= 30 && n <= 37:
+			s.fg = consoleColour(n - 30)
+		case n == 39:
+			s.fg = s.def & consoleFgMask
+		case n >= 40 && n <= 47:
+			s.bg = consoleColour(n - 40)
+		case n == 49:
+			s.bg = (s.def & consoleBgMask) >> 4
+		case n >= 90 && n <= 97:
+			s.fg = consoleColour(n-90) | consoleFgIntensity
+		case n >= 100 && n <= 107:
+			s.bg = consoleColour(n-100) | consoleFgIntensity
+		case n == 38 || n == 48 || n == 58:
+			// extended colours have no console equivalent, skip their
+			// arguments so they are not read as attributes
+			if i+1 < len(params) {
+				switch params[i+1] {
+				case 5:
+					i += 2
+				case 2:
+					i += 4
+				}
+			}
+		}
+	}
+}
+
+// attr returns the console character attributes for the current state.
+func (s *consoleState) attr() uint16 {
+	fg, bg := s.fg, s.bg
+	if s.bold {
+		fg |= consoleFgIntensity
+	}
+	if s.reverse {
+		fg, bg = bg, fg
+	}
+
+	return s.def&^(consoleFgMask|consoleBgMask) | fg | bg<<4
+}
+
+// consoleColour maps an ANSI colour index (0-7) to console colour bits. ANSI
+// orders the bits red, green, blue while the console uses blue, green, red.
+func consoleColour(n int) uint16 {
+	var c uint16
+	if n&1 != 0 {
+		c |= consoleFgRed
+	}
+	if n&2 != 0 {
+		c |= consoleFgGreen
+	}
+	if n&4 != 0 {
+		c |= consoleFgBlue
+	}
+
+	return c
+}
+
+// consoleWriter strips escape sequences from its input, writing the plain
+// text to w and translating SGR sequences into calls to setAttr.
+type consoleWriter struct {
+	w       io.Writer
+	setAttr func(uint16) error
+	state   *consoleState
+	pending string // incomplete escape sequence from the previous write
+}
+
+func newConsoleWriter(w io.Writer, def uint16, setAttr func(uint16) error) *consoleWriter {
+	return &consoleWriter{w: w, setAttr: setAttr, state: newConsoleState(def)}
+}
+
+func (cw *consoleWriter) Write(p []byte) (int, error) {
+	s := cw.pending + string(p)
+	cw.pending = ""
+
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] != escape[0] {
+			continue
+		}
+
+		if err := cw.text(s[start:i]); err != nil {
+			return 0, err
+		}
+
+		n := escapeLen(s[i:])
+		if n < 0 {
+			cw.pending = s[i:]
+			return len(p), nil
+		}
+
+		seq := s[i : i+n]
+		if isSGR(seq) {
+			cw.state.apply(parseParams(seq[2 : len(seq)-1]))
+			if err := cw.setAttr(cw.state.attr()); err != nil {
+				return 0, err
+			}
+		}
+
+		i += n - 1
+		start = i + 1
+	}
+
+	if err := cw.text(s[start:]); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+func (cw *consoleWriter) text(s string) error {
+	if s == "" {
+		return nil
+	}
+
+	_, err := io.WriteString(cw.w, s)
+	return err
+}
+
+// parseParams splits the parameters of an SGR sequence. An empty list is
+// equivalent to a reset. Colon separated sub-parameters are reduced to their
+// leading parameter.
+func parseParams(s string) []int {
+	if s == "" {
+		return []int{0}
+	}
+
+	fields := strings.Split(s, ";")
+	params := make([]int, 0, len(fields))
+	for _, f := range fields {
+		if i := strings.IndexByte(f, ':'); i >= 0 {
+			f = f[:i]
+		}
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			n = 0
+		}
+		params = append(params, n)
+	}
+
+	return params
+}
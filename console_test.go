@@ -0,0 +1,82 @@
+package colour
+
+import (
+	"bytes"
+	"testing"
+)
+
+// The matrix below describes how attribute combinations map onto legacy
+// console attributes, starting from the usual light grey on black (0x07).
+// Bold has no console equivalent and intensifies the foreground instead, so
+// it must survive any colour that follows it and must never leak into the
+// background.
+func TestConsoleAttributes(t *testing.T) {
+	const def = 0x07
+
+	tests := []struct {
+		name  string
+		attrs []Attribute
+		want  uint16
+	}{
+		{"default", nil, 0x07},
+		{"reset", []Attribute{Reset}, 0x07},
+		{"red", []Attribute{FgRed}, 0x04},
+		{"bold", []Attribute{Bold}, 0x0f},
+		{"red bold", []Attribute{FgRed, Bold}, 0x0c},
+		{"bold red", []Attribute{Bold, FgRed}, 0x0c},
+		{"red bold bg white", []Attribute{FgRed, Bold, BgWhite}, 0x7c},
+		{"bg white bold red", []Attribute{BgWhite, Bold, FgRed}, 0x7c},
+		{"bold bg white", []Attribute{Bold, BgWhite}, 0x7f},
+		{"hi red", []Attribute{FgHiRed}, 0x0c},
+		{"hi red bold", []Attribute{FgHiRed, Bold}, 0x0c},
+		{"bg hi black", []Attribute{BgHiBlack}, 0x87},
+		{"bg hi white bold blue", []Attribute{BgHiWhite, Bold, FgBlue}, 0xf9},
+		{"bold normal", []Attribute{Bold, FgRed, 22}, 0x04},
+		{"reverse", []Attribute{FgRed, BgWhite, ReverseVideo}, 0x47},
+		{"reverse bold", []Attribute{FgRed, BgWhite, ReverseVideo, Bold}, 0xc7},
+		{"fg default", []Attribute{FgRed, Bold, 39}, 0x0f},
+		{"bg default", []Attribute{BgRed, 49}, 0x07},
+		{"underline colour", []Attribute{FgRed, 58, 5, 1, 59}, 0x04},
+	}
+
+	for _, tt := range tests {
+		s := newConsoleState(def)
+		params := make([]int, len(tt.attrs))
+		for i, a := range tt.attrs {
+			params[i] = int(a)
+		}
+		s.apply(params)
+
+		if got := s.attr(); got != tt.want {
+			t.Errorf("%s: want: %#04x, got: %#04x", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestConsoleWriter(t *testing.T) {
+	var text bytes.Buffer
+	var attrs []uint16
+
+	cw := newConsoleWriter(&text, 0x07, func(a uint16) error {
+		attrs = append(attrs, a)
+		return nil
+	})
+
+	// split a sequence across writes
+	cw.Write([]byte("a\x1b[31;1"))
+	cw.Write([]byte(";47mred\x1b[0m\x1b]8;;x\x1b\\b"))
+
+	if got := text.String(); got != "aredb" {
+		t.Errorf("want: %q, got: %q", "aredb", got)
+	}
+
+	want := []uint16{0x7c, 0x07}
+	if len(attrs) != len(want) {
+		t.Fatalf("want: %#v, got: %#v", want, attrs)
+	}
+	for i := range want {
+		if attrs[i] != want[i] {
+			t.Errorf("[%d] want: %#04x, got: %#04x", i, want[i], attrs[i])
+		}
+	}
+}
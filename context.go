@@ -0,0 +1,64 @@
+package colour
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// noColourKey is the context key of the value set by WithNoColour.
+type noColourKey struct{}
+
+// WithNoColour returns a copy of ctx in which colour is disabled, or enabled
+// if disabled is false, for the print functions taking a context such as
+// FprintCtx. This lets request scoped code decide on colour without changing
+// NoColour for everyone.
+func WithNoColour(ctx context.Context, disabled bool) context.Context {
+	return context.WithValue(ctx, noColourKey{}, disabled)
+}
+
+// NoColourFromContext returns the value set with WithNoColour on ctx, with ok
+// false if there is none.
+func NoColourFromContext(ctx context.Context) (disabled, ok bool) {
+	disabled, ok = ctx.Value(noColourKey{}).(bool)
+	return disabled, ok
+}
+
+// FprintCtx is like c.Fprint, except that if colour was enabled or disabled
+// on ctx with WithNoColour that takes the place of the global NoColour. A
+// colour disabled or enabled with its own DisableColour or EnableColour
+// keeps doing so.
+func FprintCtx(ctx context.Context, w io.Writer, c *Colour, a ...interface{}) (n int, err error) {
+	return fprintCtx(ctx, w, c, fmt.Sprint(a...))
+}
+
+// FprintfCtx is like FprintCtx but formats like c.Fprintf.
+func FprintfCtx(ctx context.Context, w io.Writer, c *Colour, format string, a ...interface{}) (n int, err error) {
+	if err := strictFormatError(format, len(a)); err != nil {
+		return 0, err
+	}
+
+	return fprintCtx(ctx, w, c, fmt.Sprintf(format, a...))
+}
+
+// FprintlnCtx is like FprintCtx but formats like c.Fprintln.
+func FprintlnCtx(ctx context.Context, w io.Writer, c *Colour, a ...interface{}) (n int, err error) {
+	return fprintCtx(ctx, w, c, fmt.Sprintln(a...))
+}
+
+// fprintCtx writes s to w coloured with c unless colour is disabled for c or
+// on ctx.
+func fprintCtx(ctx context.Context, w io.Writer, c *Colour, s string) (int, error) {
+	s = sanitize(s)
+
+	disabled, ok := NoColourFromContext(ctx)
+	if c.ownNoColour() != nil || !ok {
+		disabled = c.isNoColourSet()
+	}
+	if disabled {
+		return io.WriteString(w, s)
+	}
+
+	prefix, suffix := c.enabledAffixes()
+	return io.WriteString(autoColorable(w), prefix+s+suffix)
+}
@@ -0,0 +1,48 @@
+package colour
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestFprintCtx(t *testing.T) {
+	defer SetNoColour(false)
+
+	off := WithNoColour(context.Background(), true)
+	on := WithNoColour(context.Background(), false)
+
+	tests := []struct {
+		ctx      context.Context
+		c        *Colour
+		noColour bool
+		want     string
+	}{
+		{context.Background(), New(FgRed), false, "\x1b[31mx\x1b[0m"},
+		{context.Background(), New(FgRed), true, "x"},
+		{off, New(FgRed), false, "x"},
+		{on, New(FgRed), true, "\x1b[31mx\x1b[0m"},
+		{on, NewIf(false, FgRed), false, "x"},
+	}
+
+	for i, tt := range tests {
+		SetNoColour(tt.noColour)
+		var buf bytes.Buffer
+		FprintCtx(tt.ctx, &buf, tt.c, "x")
+		if got := buf.String(); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+	}
+
+	SetNoColour(false)
+	var buf bytes.Buffer
+	FprintfCtx(off, &buf, New(FgRed), "%d;", 1)
+	FprintlnCtx(on, &buf, New(FgRed), "y")
+	if got, want := buf.String(), "1;\x1b[31my\n\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+
+	if _, ok := NoColourFromContext(context.Background()); ok {
+		t.Error("value found on an empty context")
+	}
+}
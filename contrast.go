@@ -0,0 +1,67 @@
+package colour
+
+import "math"
+
+// lightBasic tells for each of the 16 basic colours, in palette order,
+// whether it is light enough for black text.
+var lightBasic = [16]bool{
+	false, false, true, true, false, false, true, true, // BgBlack to BgWhite
+	true, true, true, true, false, true, true, true, // BgHiBlack to BgHiWhite
+}
+
+// ContrastingText returns a colour with a black or white foreground, FgBlack
+// or FgHiWhite, whichever is more readable on the background of bg, for
+// example for badges or highlighted cells. For extended backgrounds the
+// choice is made from the relative luminance of the colour, as defined by
+// WCAG, taking black if it exceeds 0.179, the point above which black gives
+// the higher contrast. For the basic backgrounds a fixed table is used, as
+// their exact colours depend on the terminal. If bg sets no background, a
+// colour without attributes is returned.
+func ContrastingText(bg *Colour) *Colour {
+	light, ok := false, false
+	for _, p := range bg.paramList() {
+		switch p := p.(type) {
+		case basicParam:
+			a := Attribute(p)
+			switch {
+			case a >= BgBlack && a <= BgWhite:
+				light, ok = lightBasic[a-BgBlack], true
+			case a >= BgHiBlack && a <= BgHiWhite:
+				light, ok = lightBasic[a-BgHiBlack+8], true
+			}
+		case indexedParam:
+			if p.kind == extendedBg {
+				light, ok = luminance(paletteRGB(uint8(p.n))) > 0.179, true
+			}
+		case trueColourParam:
+			if p.kind == extendedBg {
+				light, ok = luminance([3]uint8{uint8(p.r), uint8(p.g), uint8(p.b)}) > 0.179, true
+			}
+		}
+	}
+
+	switch {
+	case !ok:
+		return New()
+	case light:
+		return New(FgBlack)
+	}
+
+	return New(FgHiWhite)
+}
+
+// luminance returns the relative luminance of an sRGB colour, from 0 for
+// black to 1 for white.
+func luminance(rgb [3]uint8) float64 {
+	var lin [3]float64
+	for i, v := range rgb {
+		c := float64(v) / 255
+		if c <= 0.03928 {
+			lin[i] = c / 12.92
+		} else {
+			lin[i] = math.Pow((c+0.055)/1.055, 2.4)
+		}
+	}
+
+	return 0.2126*lin[0] + 0.7152*lin[1] + 0.0722*lin[2]
+}
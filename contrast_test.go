@@ -0,0 +1,38 @@
+package colour
+
+import "testing"
+
+func TestContrastingText(t *testing.T) {
+	tests := []struct {
+		bg   *Colour
+		want *Colour
+	}{
+		{New(BgBlack), New(FgHiWhite)},
+		{New(BgYellow), New(FgBlack)},
+		{New(BgHiBlue), New(FgHiWhite)},
+		{New(BgHiWhite, Bold), New(FgBlack)},
+		{BgRGB(255, 255, 255), New(FgBlack)},
+		{BgRGB(0, 0, 128), New(FgHiWhite)},
+		{BgRGB(255, 128, 0), New(FgBlack)},
+		{NewBg256(226), New(FgBlack)},
+		{NewBg256(17), New(FgHiWhite)},
+		{New(BgWhite).AddBgRGB(0, 0, 0), New(FgHiWhite)},
+		{NewFg256(226), New()},
+		{New(FgRed), New()},
+	}
+
+	for i, tt := range tests {
+		if got := ContrastingText(tt.bg); !got.Equals(tt.want) {
+			t.Errorf("[%d] want: %v, got: %v", i, tt.want.Attributes(), got.Attributes())
+		}
+	}
+}
+
+func TestLuminance(t *testing.T) {
+	if l := luminance([3]uint8{0, 0, 0}); l != 0 {
+		t.Errorf("black: want: 0, got: %v", l)
+	}
+	if l := luminance([3]uint8{255, 255, 255}); l < 0.9999 || l > 1.0001 {
+		t.Errorf("white: want: 1, got: %v", l)
+	}
+}
@@ -0,0 +1,32 @@
+package colour
+
+import "testing"
+
+func TestCSS(t *testing.T) {
+	tests := []struct {
+		name string
+		want *Colour
+	}{
+		{"tomato", RGB(255, 99, 71)},
+		{"rebeccapurple", RGB(102, 51, 153)},
+		{"RebeccaPurple", RGB(102, 51, 153)},
+		{"grey", RGB(128, 128, 128)},
+		{"gray", RGB(128, 128, 128)},
+	}
+
+	for i, tt := range tests {
+		got, err := CSS(tt.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equals(tt.want) {
+			t.Errorf("[%d] want: %v, got: %v", i, tt.want.Attributes(), got.Attributes())
+		}
+	}
+
+	for _, name := range []string{"", "notacolour", "tomato "} {
+		if _, err := CSS(name); err == nil {
+			t.Errorf("%q: want error", name)
+		}
+	}
+}
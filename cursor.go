@@ -0,0 +1,52 @@
+package colour
+
+import "strconv"
+
+// Sequences controlling the cursor and erasing parts of the screen.
+const (
+	clearToEOL = escape + "[K"
+	clearLine  = escape + "[2K"
+	cursorHide = escape + "[?25l"
+	cursorShow = escape + "[?25h"
+)
+
+// The functions below return sequences controlling the cursor, for progress
+// output and similar. Like colours they are only meant for terminals, so they
+// return an empty string if NoColour is set, which it is by default when
+// stdout is not a terminal.
+
+// ClearLine returns the sequence erasing the line the cursor is on, followed
+// by a carriage return moving the cursor to the start of the line.
+func ClearLine() string {
+	return cursorSequence(clearLine + "\r")
+}
+
+// MoveUp returns the sequence moving the cursor up n lines, staying in the
+// same column. It returns an empty string if n is not positive.
+func MoveUp(n int) string {
+	if n <= 0 {
+		return ""
+	}
+
+	return cursorSequence(escape + "[" + strconv.Itoa(n) + "A")
+}
+
+// CursorHide returns the sequence hiding the cursor. Make sure to show it
+// again with CursorShow before exiting.
+func CursorHide() string {
+	return cursorSequence(cursorHide)
+}
+
+// CursorShow returns the sequence showing the cursor again after CursorHide.
+func CursorShow() string {
+	return cursorSequence(cursorShow)
+}
+
+// cursorSequence returns seq, or an empty string if NoColour is set.
+func cursorSequence(seq string) string {
+	if GetNoColour() {
+		return ""
+	}
+
+	return seq
+}
@@ -0,0 +1,30 @@
+package colour
+
+import "testing"
+
+func TestCursor(t *testing.T) {
+	defer func(v bool) { NoColour = v }(NoColour)
+
+	tests := []struct {
+		noColour bool
+		got      func() string
+		want     string
+	}{
+		{false, ClearLine, "\x1b[2K\r"},
+		{false, func() string { return MoveUp(3) }, "\x1b[3A"},
+		{false, func() string { return MoveUp(0) }, ""},
+		{false, CursorHide, "\x1b[?25l"},
+		{false, CursorShow, "\x1b[?25h"},
+		{true, ClearLine, ""},
+		{true, func() string { return MoveUp(3) }, ""},
+		{true, CursorHide, ""},
+		{true, CursorShow, ""},
+	}
+
+	for i, tt := range tests {
+		NoColour = tt.noColour
+		if got := tt.got(); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+	}
+}
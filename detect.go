@@ -0,0 +1,52 @@
+package colour
+
+import (
+	"io"
+
+	"github.com/mattn/go-isatty"
+)
+
+// SupportsColour reports whether colours should be written to w. This is the
+// case for files, or other writers exposing a file descriptor with an Fd
+// method, referring to a terminal, including the Cygwin and MSYS terminals
+// on Windows. The environment overrides this as described for NoColour:
+// FORCE_COLOR enables or disables colour for any writer, and otherwise
+// NO_COLOR and TERM=dumb disable it.
+func SupportsColour(w io.Writer) bool {
+	return !detectNoColour(isTerminal(w))
+}
+
+// isTerminal reports whether w has a file descriptor referring to a
+// terminal, including Cygwin and MSYS terminals.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(interface{ Fd() uintptr })
+	if !ok {
+		return false
+	}
+
+	fd := f.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
+// FprintAuto is like Fprint but only colours the output if w supports colour
+// according to SupportsColour, writing plain text otherwise. This lets
+// libraries colour output depending on where it goes rather than on stdout.
+func (c *Colour) FprintAuto(w io.Writer, a ...interface{}) (n int, err error) {
+	if !SupportsColour(w) {
+		return fprint(w, a...)
+	}
+
+	return c.Fprint(w, a...)
+}
+
+// FprintAuto is like the method of the same name, as a function usable with
+// any colour. Arguments are written as plain text if c is nil:
+//
+//	colour.FprintAuto(os.Stderr, colour.New(colour.FgRed), "failed")
+func FprintAuto(w io.Writer, c *Colour, a ...interface{}) (n int, err error) {
+	if c == nil {
+		return fprint(w, a...)
+	}
+
+	return c.FprintAuto(w, a...)
+}
@@ -0,0 +1,68 @@
+package colour
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestSupportsColour(t *testing.T) {
+	defer setenv("TERM", strPtr("xterm"))()
+	defer setenv("NO_COLOR", nil)()
+	defer setenv("FORCE_COLOR", nil)()
+
+	f, err := ioutil.TempFile("", "colour")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if SupportsColour(f) {
+		t.Error("Regular file supports colour")
+	}
+	if SupportsColour(new(bytes.Buffer)) {
+		t.Error("Buffer supports colour")
+	}
+
+	os.Setenv("FORCE_COLOR", "1")
+	if !SupportsColour(new(bytes.Buffer)) {
+		t.Error("Buffer does not support colour with FORCE_COLOR set")
+	}
+
+	os.Setenv("FORCE_COLOR", "0")
+	if SupportsColour(f) {
+		t.Error("Regular file supports colour with FORCE_COLOR=0")
+	}
+}
+
+func TestFprintAuto(t *testing.T) {
+	defer setenv("FORCE_COLOR", nil)()
+	NoColour = false
+
+	var buf bytes.Buffer
+	New(FgRed).FprintAuto(&buf, "plain")
+	if got, want := buf.String(), "plain"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+}
+
+func TestFprintAutoFunc(t *testing.T) {
+	defer setenv("FORCE_COLOR", nil)()
+	NoColour = false
+
+	var buf bytes.Buffer
+	FprintAuto(&buf, New(FgRed), "a")
+	FprintAuto(&buf, nil, "b")
+	if got, want := buf.String(), "ab"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+
+	os.Setenv("FORCE_COLOR", "1")
+	buf.Reset()
+	FprintAuto(&buf, New(FgRed), "a")
+	if got, want := buf.String(), "\x1b[31ma\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+}
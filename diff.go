@@ -0,0 +1,202 @@
+package colour
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DiffMode selects the granularity of Diff.
+type DiffMode int
+
+const (
+	// DiffLines compares whole lines and shows the result like a unified
+	// diff, each line prefixed with "+", "-" or a space.
+	DiffLines DiffMode = iota
+
+	// DiffWords compares words and shows the new text with deleted and
+	// inserted words inline.
+	DiffWords
+)
+
+// DiffOptions configures DiffWith. The zero value gives a line diff with
+// additions in green and deletions in red.
+type DiffOptions struct {
+	Mode DiffMode
+
+	// Add and Del colour inserted and deleted text, FgGreen and FgRed if
+	// nil.
+	Add *Colour
+	Del *Colour
+}
+
+// Diff returns a line diff of oldText and newText with added lines in green
+// and deleted lines in red. Every line of both texts is shown, prefixed as in
+// a unified diff, without headers or hunks. If colour is disabled globally
+// the diff is returned uncoloured.
+func Diff(oldText, newText string) string {
+	return DiffWith(oldText, newText, nil)
+}
+
+// DiffWith is like Diff but with the mode and colours given by opts, which
+// may be nil for the defaults. In DiffWords mode words and the whitespace
+// between them are compared, and the deleted and added runs are shown inline
+// in the new text. Without colour they are marked as [-deleted-] and
+// {+added+} instead, as git's plain word diff does.
+func DiffWith(oldText, newText string, opts *DiffOptions) string {
+	var o DiffOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Add == nil {
+		o.Add = New(FgGreen)
+	}
+	if o.Del == nil {
+		o.Del = New(FgRed)
+	}
+
+	if o.Mode == DiffWords {
+		return wordDiff(oldText, newText, o.Add, o.Del)
+	}
+
+	return lineDiff(oldText, newText, o.Add, o.Del)
+}
+
+// diffOp is a run of tokens kept, inserted or deleted by a diff.
+type diffOp struct {
+	kind byte // ' ', '+' or '-'
+	text []string
+}
+
+// lineDiff shows the lines of oldText and newText prefixed as in a unified
+// diff.
+func lineDiff(oldText, newText string, add, del *Colour) string {
+	var b strings.Builder
+	for _, op := range diffTokens(splitLines(oldText), splitLines(newText)) {
+		for _, l := range op.text {
+			line := string(op.kind) + strings.TrimSuffix(l, "\n")
+			switch op.kind {
+			case '+':
+				line = add.wrap(line)
+			case '-':
+				line = del.wrap(line)
+			}
+			b.WriteString(line)
+			b.WriteByte('\n')
+		}
+	}
+
+	return b.String()
+}
+
+// wordDiff shows newText with the words deleted from oldText and inserted
+// into it marked inline.
+func wordDiff(oldText, newText string, add, del *Colour) string {
+	plain := colourDisabled()
+
+	var b strings.Builder
+	for _, op := range diffTokens(splitWords(oldText), splitWords(newText)) {
+		s := strings.Join(op.text, "")
+		switch {
+		case op.kind == ' ':
+			b.WriteString(s)
+		case plain && op.kind == '+':
+			b.WriteString("{+" + s + "+}")
+		case plain:
+			b.WriteString("[-" + s + "-]")
+		case op.kind == '+':
+			b.WriteString(add.wrap(s))
+		default:
+			b.WriteString(del.wrap(s))
+		}
+	}
+
+	return b.String()
+}
+
+// splitLines splits s into lines, each keeping its newline, so that a missing
+// newline at the end of a text counts as a change.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// splitWords splits s into runs of whitespace and of other runes.
+func splitWords(s string) []string {
+	var words []string
+	start := 0
+	for i, r := range s {
+		if i > start && unicode.IsSpace(r) != isSpaceAt(s, start) {
+			words = append(words, s[start:i])
+			start = i
+		}
+	}
+	if start < len(s) {
+		words = append(words, s[start:])
+	}
+
+	return words
+}
+
+// isSpaceAt reports whether the rune starting at byte i of s is whitespace.
+func isSpaceAt(s string, i int) bool {
+	for _, r := range s[i:] {
+		return unicode.IsSpace(r)
+	}
+
+	return false
+}
+
+// diffTokens returns the operations turning a into b, found from a longest
+// common subsequence of the two. Deletions are placed before insertions where
+// both replace the same tokens.
+func diffTokens(a, b []string) []diffOp {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []diffOp
+	emit := func(kind byte, s string) {
+		if n := len(ops); n > 0 && ops[n-1].kind == kind {
+			ops[n-1].text = append(ops[n-1].text, s)
+			return
+		}
+		ops = append(ops, diffOp{kind: kind, text: []string{s}})
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			emit(' ', a[i])
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			emit('-', a[i])
+			i++
+		default:
+			emit('+', b[j])
+			j++
+		}
+	}
+
+	return ops
+}
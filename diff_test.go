@@ -0,0 +1,42 @@
+package colour
+
+import "testing"
+
+func TestDiff(t *testing.T) {
+	NoColour = false
+
+	tests := []struct {
+		old, new string
+		opts     *DiffOptions
+		want     string
+	}{
+		{"a\nb\nc\n", "a\nx\nc\n", nil, " a\n\x1b[31m-b\x1b[0m\n\x1b[32m+x\x1b[0m\n c\n"},
+		{"a\nb\n", "a\nb\nc", nil, " a\n b\n\x1b[32m+c\x1b[0m\n"},
+		{"a\nb", "a\nb\n", nil, " a\n\x1b[31m-b\x1b[0m\n\x1b[32m+b\x1b[0m\n"},
+		{"", "", nil, ""},
+		{"a\n", "a\n", &DiffOptions{Add: New(FgBlue)}, " a\n"},
+		{"a\n", "", &DiffOptions{Del: New(FgMagenta)}, "\x1b[35m-a\x1b[0m\n"},
+		{"the quick fox", "the slow fox jumps", &DiffOptions{Mode: DiffWords}, "the \x1b[31mquick\x1b[0m\x1b[32mslow\x1b[0m fox\x1b[32m jumps\x1b[0m"},
+		{"one  two", "one two", &DiffOptions{Mode: DiffWords}, "one\x1b[31m  \x1b[0m\x1b[32m \x1b[0mtwo"},
+	}
+
+	for i, tt := range tests {
+		if got := DiffWith(tt.old, tt.new, tt.opts); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+	}
+}
+
+func TestDiffNoColour(t *testing.T) {
+	NoColour = true
+	defer func() { NoColour = false }()
+
+	if got, want := Diff("a\nb\n", "a\nc\n"), " a\n-b\n+c\n"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+
+	got := DiffWith("the quick fox", "the slow fox", &DiffOptions{Mode: DiffWords})
+	if want := "the [-quick-]{+slow+} fox"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+}
@@ -88,6 +88,23 @@ set the output to colour.Output:
     info := New(FgWhite, BgGreen).SprintFunc()
     fmt.Fprintf(colour.Output, "this %s rocks!\n", info("package"))
 
+Legacy consoles without VT support rely on go-colorable to translate escape
+sequences, which can render bold combined with a background colour
+incorrectly. UseNativeWindows switches Output and Error to writers driving the
+console API directly, where bold always intensifies the foreground:
+
+    if err := colour.UseNativeWindows(); err != nil {
+    	// not a console, keep the default writers
+    }
+
+On Windows 10 and later the console can interpret escape sequences itself.
+EnableVirtualTerminalProcessing turns this on and writes to os.Stdout without
+any translation:
+
+    if disable, err := colour.EnableVirtualTerminalProcessing(); err == nil {
+    	defer disable()
+    }
+
 Using with existing code is possible. Just use the Set() method to set the
 standard output to the given parameters. That way a rewrite of an existing
 code is not required.
@@ -118,6 +135,15 @@ the colour output with:
     	colour.NoColour = true // disables colourized output
     }
 
+Use SetNoColour instead of assigning NoColour if other goroutines may be
+printing at the same time.
+
+Colour output is also disabled when the NO_COLOR environment variable is set
+to a non-empty value, see https://no-color.org. Setting FORCE_COLOR enables
+colour even if the output is not a terminal, with 1, 2 and 3 selecting 16,
+256 and truecolour output, and 0 or false disabling it. FORCE_COLOR takes
+precedence over NO_COLOR.
+
 It also has support for single colour definitions (local). You can
 disable/enable colour output on the fly:
 
@@ -0,0 +1,90 @@
+package colour
+
+import "strings"
+
+// ReplaceVisible replaces every occurrence of old in the visible text of the
+// coloured string s with replacement, styled by c. Matches are found ignoring
+// escape sequences, so old may span several styled regions. The style in
+// effect after each match is opened again once the replacement has been
+// written, keeping the surrounding styling intact. If c is nil or disabled,
+// the replacement is written plain and takes on the style around it.
+func ReplaceVisible(s, old, replacement string, c *Colour) string {
+	if old == "" {
+		return s
+	}
+
+	v, pos := visibleText(s)
+
+	styledNew := c != nil && !c.isNoColourSet()
+
+	var b strings.Builder
+	var st sgrState
+	last := 0
+	for vi := 0; ; {
+		j := strings.Index(v[vi:], old)
+		if j < 0 {
+			break
+		}
+		start, end := pos[vi+j], pos[vi+j+len(old)-1]+1
+		vi += j + len(old)
+
+		b.WriteString(s[last:start])
+		st.feed(s[last:start])
+		open := st.active != ""
+
+		// SGR sequences inside the match still apply to what follows
+		var inner strings.Builder
+		for i := start; i < end; i++ {
+			if s[i] != escape[0] {
+				continue
+			}
+			l := escapeLen(s[i:])
+			if l < 0 {
+				break
+			}
+			if seq := s[i : i+l]; isSGR(seq) {
+				st.update(seq)
+				inner.WriteString(seq)
+			}
+			i += l - 1
+		}
+
+		if styledNew {
+			if open {
+				b.WriteString(resetSequence)
+			}
+			b.WriteString(c.wrap(replacement))
+			b.WriteString(st.active)
+		} else {
+			b.WriteString(replacement)
+			b.WriteString(inner.String())
+		}
+		last = end
+	}
+	b.WriteString(s[last:])
+
+	return b.String()
+}
+
+// visibleText returns the visible text of s and, for each of its bytes, its
+// offset in s. Escape sequences are skipped, including an incomplete one at
+// the end of s.
+func visibleText(s string) (string, []int) {
+	var vis strings.Builder
+	pos := make([]int, 0, len(s))
+	for i := 0; i < len(s); {
+		if s[i] == escape[0] {
+			l := escapeLen(s[i:])
+			if l < 0 {
+				l = len(s) - i
+			}
+			i += l
+			continue
+		}
+		vis.WriteByte(s[i])
+		pos = append(pos, i)
+		i++
+	}
+
+	return vis.String(), pos
+}
@@ -0,0 +1,42 @@
+package colour
+
+import "testing"
+
+func TestReplaceVisible(t *testing.T) {
+	NoColour = false
+	blue := New(FgBlue)
+
+	tests := []struct {
+		s, old, new string
+		c           *Colour
+		want        string
+	}{
+		{"hello world", "world", "there", nil, "hello there"},
+		{"hello world", "missing", "x", blue, "hello world"},
+		{"a{x}b{x}", "{x}", "1", nil, "a1b1"},
+		{
+			"hello world", "world", "there", blue,
+			"hello \x1b[34mthere\x1b[0m",
+		},
+		{
+			"\x1b[31mhello world!\x1b[0m", "world", "there", blue,
+			"\x1b[31mhello \x1b[0m\x1b[34mthere\x1b[0m\x1b[31m!\x1b[0m",
+		},
+		{
+			// the match spans a style change
+			"\x1b[31mhello wo\x1b[1mrld!\x1b[0m", "world", "there", nil,
+			"\x1b[31mhello there\x1b[1m!\x1b[0m",
+		},
+		{
+			"\x1b[31mhello wo\x1b[0mrld!", "world", "there", blue,
+			"\x1b[31mhello \x1b[0m\x1b[34mthere\x1b[0m!",
+		},
+		{"\x1b[32mgrüße\x1b[0m", "ü", "ue", nil, "\x1b[32mgrueße\x1b[0m"},
+	}
+
+	for i, tt := range tests {
+		if got := ReplaceVisible(tt.s, tt.old, tt.new, tt.c); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package colour
+
+// Extended colour attributes. They are followed by 5 and an index into the
+// 256 colour palette, or by 2 and the red, green and blue components.
+const (
+	extendedFg              Attribute = 38
+	extendedBg              Attribute = 48
+	extendedUnderlineColour Attribute = 58
+
+	extendedIndexed Attribute = 5
+	extendedRGB     Attribute = 2
+)
+
+// NewFg256 returns a new colour object with the foreground set to the 256
+// colour palette entry n.
+func NewFg256(n uint8) *Colour {
+	return New().AddFg256(n)
+}
+
+// NewBg256 returns a new colour object with the background set to the 256
+// colour palette entry n.
+func NewBg256(n uint8) *Colour {
+	return New().AddBg256(n)
+}
+
+// AddFg256 adds a foreground from the 256 colour palette, rendered as
+// "38;5;n".
+func (c *Colour) AddFg256(n uint8) *Colour {
+	return c.addParam(indexedParam{extendedFg, Attribute(n)})
+}
+
+// AddBg256 adds a background from the 256 colour palette, rendered as
+// "48;5;n".
+func (c *Colour) AddBg256(n uint8) *Colour {
+	return c.addParam(indexedParam{extendedBg, Attribute(n)})
+}
+
+// Fg256String is a convenient helper function to return a string with the
+// foreground set to the 256 colour palette entry n.
+func Fg256String(n uint8, format string, a ...interface{}) string {
+	coloursCacheMu.Lock()
+	c, ok := fg256Cache[n]
+	if !ok {
+		c = NewFg256(n)
+		fg256Cache[n] = c
+	}
+	coloursCacheMu.Unlock()
+
+	return sprintColour(c, format, a...)
+}
+
+// param is a unit of SGR parameters taking effect together, a single
+// attribute or an extended colour. Implementations are comparable, so params
+// can be compared with ==.
+type param interface {
+	// attrs returns the SGR parameters of the unit, never empty.
+	attrs() []Attribute
+}
+
+// basicParam is an attribute on its own, such as Bold or FgRed.
+type basicParam Attribute
+
+func (p basicParam) attrs() []Attribute {
+	return []Attribute{Attribute(p)}
+}
+
+// indexedParam is a colour from the 256 colour palette, "38;5;n" for the
+// foreground kind.
+type indexedParam struct {
+	kind Attribute // extendedFg, extendedBg or extendedUnderlineColour
+	n    Attribute
+}
+
+func (p indexedParam) attrs() []Attribute {
+	return []Attribute{p.kind, extendedIndexed, p.n}
+}
+
+// trueColourParam is a truecolour, "38;2;r;g;b" for the foreground kind.
+type trueColourParam struct {
+	kind    Attribute // extendedFg, extendedBg or extendedUnderlineColour
+	r, g, b Attribute
+}
+
+func (p trueColourParam) attrs() []Attribute {
+	return []Attribute{p.kind, extendedRGB, p.r, p.g, p.b}
+}
+
+// appendParams appends the units formed by attrs to params. Extended colours
+// cut short are kept as separate attributes.
+func appendParams(params []param, attrs []Attribute) []param {
+	for i := 0; i < len(attrs); {
+		switch n := paramLen(attrs, i); n {
+		case 3:
+			params = append(params, indexedParam{attrs[i], attrs[i+2]})
+			i += n
+		case 5:
+			params = append(params, trueColourParam{attrs[i], attrs[i+2], attrs[i+3], attrs[i+4]})
+			i += n
+		default:
+			params = append(params, basicParam(attrs[i]))
+			i++
+		}
+	}
+
+	return params
+}
+
+// paramLen returns the number of parameters starting at params[i] that form
+// a single unit, such as the three of "38;5;n".
+func paramLen(params []Attribute, i int) int {
+	n := 1
+	switch params[i] {
+	case extendedFg, extendedBg, extendedUnderlineColour:
+		if i+1 < len(params) {
+			switch params[i+1] {
+			case extendedIndexed:
+				n = 3
+			case extendedRGB:
+				n = 5
+			}
+		}
+	}
+
+	if i+n > len(params) {
+		n = len(params) - i
+	}
+
+	return n
+}
+
+// addParam adds p to the parameters of c and returns c.
+func (c *Colour) addParam(p param) *Colour {
+	c.mu.Lock()
+	c.params = append(c.params[:len(c.params):len(c.params)], p)
+	c.gen++
+	c.mu.Unlock()
+
+	return c
+}
+
+// groups returns the SGR parameters of each unit of c.
+func (c *Colour) groups() [][]Attribute {
+	params := c.paramList()
+	groups := make([][]Attribute, 0, len(params))
+	for _, p := range params {
+		groups = append(groups, p.attrs())
+	}
+
+	return groups
+}
+
+// isForeground reports whether the parameter group g sets the foreground.
+func isForeground(g []Attribute) bool {
+	a := g[0]
+	return (a >= FgBlack && a <= FgWhite) || (a >= FgHiBlack && a <= FgHiWhite) || a == extendedFg
+}
+
+// isBackground reports whether the parameter group g sets the background.
+func isBackground(g []Attribute) bool {
+	a := g[0]
+	return (a >= BgBlack && a <= BgWhite) || (a >= BgHiBlack && a <= BgHiWhite) || a == extendedBg
+}
+
+// RGB returns a new colour object with a truecolour foreground. Terminals
+// supporting fewer colours are given the nearest colour they can display.
+func RGB(r, g, b uint8) *Colour {
+	return New().AddRGB(r, g, b)
+}
+
+// BgRGB returns a new colour object with a truecolour background. Terminals
+// supporting fewer colours are given the nearest colour they can display.
+func BgRGB(r, g, b uint8) *Colour {
+	return New().AddBgRGB(r, g, b)
+}
+
+// RGBString is a convenient helper function to return a string with a
+// truecolour foreground. Unlike the other helpers its colours are not
+// cached, as there are too many of them.
+func RGBString(r, g, b uint8, format string, a ...interface{}) string {
+	return sprintColour(RGB(r, g, b), format, a...)
+}
+
+// AddRGB adds a truecolour foreground, rendered as "38;2;r;g;b".
+func (c *Colour) AddRGB(r, g, b uint8) *Colour {
+	return c.addParam(trueColourParam{extendedFg, Attribute(r), Attribute(g), Attribute(b)})
+}
+
+// AddBgRGB adds a truecolour background, rendered as "48;2;r;g;b".
+func (c *Colour) AddBgRGB(r, g, b uint8) *Colour {
+	return c.addParam(trueColourParam{extendedBg, Attribute(r), Attribute(g), Attribute(b)})
+}
+
+// DefaultUnderlineColour resets the underline colour set with
+// UnderlineColour256 or UnderlineColourRGB, so underlines take the colour of
+// the text again.
+const DefaultUnderlineColour Attribute = 59
+
+// UnderlineColour256 sets the colour of underlines to the 256 colour palette
+// entry n, rendered as "58;5;n", independent of the text colour. It has no
+// effect on its own, add Underline or one of the other underline styles too.
+func (c *Colour) UnderlineColour256(n uint8) *Colour {
+	return c.addParam(indexedParam{extendedUnderlineColour, Attribute(n)})
+}
+
+// UnderlineColourRGB sets the colour of underlines to a truecolour, rendered
+// as "58;2;r;g;b", independent of the text colour.
+func (c *Colour) UnderlineColourRGB(r, g, b uint8) *Colour {
+	return c.addParam(trueColourParam{extendedUnderlineColour, Attribute(r), Attribute(g), Attribute(b)})
+}
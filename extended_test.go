@@ -0,0 +1,125 @@
+package colour
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test256Colour(t *testing.T) {
+	NoColour = false
+	defer SetLevel(GetLevel())
+	SetLevel(Level256)
+
+	tests := []struct {
+		c    *Colour
+		want string
+	}{
+		{NewFg256(196), "\x1b[38;5;196mx\x1b[0m"},
+		{NewBg256(0), "\x1b[48;5;0mx\x1b[0m"},
+		{New(Bold).AddFg256(2).AddBg256(255), "\x1b[1;38;5;2;48;5;255mx\x1b[0m"},
+	}
+
+	for i, tt := range tests {
+		if got := tt.c.Sprint("x"); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+	}
+
+	if got, want := Swatch(NewBg256(21)), "\x1b[48;5;21m  \x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+}
+
+func Test256ColourEquals(t *testing.T) {
+	tests := []struct {
+		a, b *Colour
+		want bool
+	}{
+		{NewFg256(196), NewFg256(196), true},
+		{NewFg256(196), NewFg256(197), false},
+		{NewFg256(5), NewBg256(5), false},
+		{NewFg256(1).AddBg256(2), NewBg256(2).AddFg256(1), true},
+		{NewFg256(1).AddBg256(2), NewFg256(2).AddBg256(1), false},
+		// same parameters, grouped differently
+		{New(Bold).AddFg256(5), New(extendedFg, extendedIndexed, Bold, BlinkSlow, 5), false},
+		{New(Bold).AddFg256(5), New(Bold, BlinkSlow).AddFg256(5), false},
+		// flat parameters and the helpers give the same units
+		{New(extendedFg, extendedIndexed, 196), NewFg256(196), true},
+		{New(extendedBg, extendedRGB, 1, 2, 3), BgRGB(1, 2, 3), true},
+		{New(FgRed).Add(extendedFg, extendedRGB, 1, 2, 3), New(FgRed).AddRGB(1, 2, 3), true},
+	}
+
+	for i, tt := range tests {
+		if got := tt.a.Equals(tt.b); got != tt.want {
+			t.Errorf("[%d] want: %v, got: %v", i, tt.want, got)
+		}
+	}
+}
+
+func TestExtendedCutShort(t *testing.T) {
+	NoColour = false
+
+	c := New(Bold, extendedFg, extendedIndexed)
+	if got, want := c.Sprint("x"), "\x1b[1;38;5mx\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+	if got, want := c.Attributes(), []Attribute{Bold, extendedFg, extendedIndexed}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want: %v, got: %v", want, got)
+	}
+}
+
+func TestUnderlineColour(t *testing.T) {
+	NoColour = false
+	defer SetLevel(GetLevel())
+	SetLevel(LevelTrueColour)
+
+	tests := []struct {
+		c    *Colour
+		want string
+	}{
+		{New(Underline).UnderlineColour256(196), "\x1b[4;58;5;196mx\x1b[0m"},
+		{New(Underline).UnderlineColourRGB(255, 0, 0), "\x1b[4;58;2;255;0;0mx\x1b[0m"},
+		{New(FgGreen, CurlyUnderline).UnderlineColour256(1), "\x1b[32;4:3;58;5;1mx\x1b[0m"},
+		{New(DefaultUnderlineColour), "\x1b[59mx\x1b[0m"},
+	}
+
+	defer SetExtendedUnderline(GetExtendedUnderline())
+	SetExtendedUnderline(true)
+	for i, tt := range tests {
+		if got := tt.c.Sprint("x"); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+	}
+
+	// the underline colour coexists with a foreground of the same value
+	c := New(FgRed).UnderlineColour256(1).AddFg256(1)
+	if got, want := c.Remove(extendedUnderlineColour).Sprint("x"), "\x1b[31;38;5;1mx\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+}
+
+func TestExtendedStringHelpers(t *testing.T) {
+	NoColour = false
+	defer SetLevel(GetLevel())
+	SetLevel(LevelTrueColour)
+	defer ClearCache()
+
+	tests := []struct {
+		got, want string
+	}{
+		{Fg256String(196, "hi"), "\x1b[38;5;196mhi\x1b[0m"},
+		{Fg256String(196, "%d%%", 5), "\x1b[38;5;196m5%\x1b[0m"},
+		{RGBString(1, 2, 3, "hi %s", "there"), "\x1b[38;2;1;2;3mhi there\x1b[0m"},
+		{RGBString(1, 2, 3, "100%"), "\x1b[38;2;1;2;3m100%\x1b[0m"},
+	}
+
+	for i, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, tt.got)
+		}
+	}
+
+	if len(fg256Cache) != 1 {
+		t.Errorf("want: 1 cached colour, got: %d", len(fg256Cache))
+	}
+}
@@ -0,0 +1,110 @@
+package colour
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// GradientOptions configures GradientWith. The zero value gives the gradient
+// of Gradient.
+type GradientOptions struct {
+	// SkipSpace leaves whitespace out of the gradient: it is not coloured
+	// and does not take a step, so the gradient spans the other runes only
+	// and reaches the final colour at the last of them.
+	SkipSpace bool
+}
+
+// Gradient returns s with the foreground of each rune interpolated linearly
+// from the colour from at the first rune to the colour to at the last. It is
+// made for truecolour terminals, others are given the nearest colours they
+// can display. A single reset ends the string. If colour is disabled globally
+// s is returned unchanged.
+func Gradient(s string, from, to [3]uint8) string {
+	return GradientWith(s, from, to, nil)
+}
+
+// GradientWith is like Gradient but with the options given by opts, which may
+// be nil for the defaults.
+func GradientWith(s string, from, to [3]uint8, opts *GradientOptions) string {
+	var o GradientOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	return colourRunes(s, o.SkipSpace, func(i, n int) [3]uint8 {
+		if n < 2 {
+			return from
+		}
+
+		var rgb [3]uint8
+		for c := range rgb {
+			d := float64(int(to[c])-int(from[c])) * float64(i) / float64(n-1)
+			rgb[c] = uint8(roundInt(float64(from[c]) + d))
+		}
+		return rgb
+	})
+}
+
+// colourRunes colours each rune of s with the foreground returned by colour
+// for its index among the n runes of s. If skipSpace is set, whitespace is
+// left uncoloured and not counted. Sequences are only written when the
+// colour changes and a single reset ends the string.
+func colourRunes(s string, skipSpace bool, colour func(i, n int) [3]uint8) string {
+	s = sanitize(s)
+	if colourDisabled() || s == "" {
+		return s
+	}
+
+	n := 0
+	for _, r := range s {
+		if !(skipSpace && unicode.IsSpace(r)) {
+			n++
+		}
+	}
+
+	var b strings.Builder
+	last := ""
+	i := 0
+	for j, r := range s {
+		if !(skipSpace && unicode.IsSpace(r)) {
+			rgb := colour(i, n)
+			if seq := RGB(rgb[0], rgb[1], rgb[2]).format(); seq != last {
+				b.WriteString(seq)
+				last = seq
+			}
+			i++
+		}
+		_, size := utf8.DecodeRuneInString(s[j:])
+		b.WriteString(s[j : j+size])
+	}
+	if last != "" {
+		b.WriteString(resetSequence)
+	}
+
+	return b.String()
+}
+
+// roundInt rounds the non-negative f to the nearest integer, rounding halves
+// up.
+func roundInt(f float64) int {
+	return int(f + 0.5)
+}
+
+// Rainbow returns s with the hue of each rune's foreground cycling once
+// through the colour wheel, starting at red. It is made for truecolour
+// terminals, others are given the nearest colours they can display. A single
+// reset ends the string. If colour is disabled globally s is returned
+// unchanged.
+func Rainbow(s string) string {
+	return RainbowWith(s, 1, 1, 0)
+}
+
+// RainbowWith is like Rainbow but with the saturation and value of the
+// colours, both in [0, 1], and the hue of the first rune given in degrees by
+// phase. Varying phase between calls animates the rainbow.
+func RainbowWith(s string, saturation, value float64, phase float64) string {
+	return colourRunes(s, false, func(i, n int) [3]uint8 {
+		return hsvToRGB(phase+360*float64(i)/float64(n), saturation, value)
+	})
+}
@@ -0,0 +1,68 @@
+package colour
+
+import "testing"
+
+func TestGradient(t *testing.T) {
+	NoColour = false
+	defer SetLevel(GetLevel())
+	SetLevel(LevelTrueColour)
+
+	black, white := [3]uint8{0, 0, 0}, [3]uint8{255, 255, 255}
+
+	tests := []struct {
+		s         string
+		from, to  [3]uint8
+		skipSpace bool
+		want      string
+	}{
+		{"", black, white, false, ""},
+		{"a", black, white, false, "\x1b[38;2;0;0;0ma\x1b[0m"},
+		{"abc", black, white, false, "\x1b[38;2;0;0;0ma\x1b[38;2;128;128;128mb\x1b[38;2;255;255;255mc\x1b[0m"},
+		{"世 界", [3]uint8{255, 0, 0}, [3]uint8{0, 0, 255}, false, "\x1b[38;2;255;0;0m世\x1b[38;2;128;0;128m \x1b[38;2;0;0;255m界\x1b[0m"},
+		{"ab c", black, white, false, "\x1b[38;2;0;0;0ma\x1b[38;2;85;85;85mb\x1b[38;2;170;170;170m \x1b[38;2;255;255;255mc\x1b[0m"},
+		{"ab c", black, white, true, "\x1b[38;2;0;0;0ma\x1b[38;2;128;128;128mb \x1b[38;2;255;255;255mc\x1b[0m"},
+		{" ab ", black, white, true, " \x1b[38;2;0;0;0ma\x1b[38;2;255;255;255mb \x1b[0m"},
+		{"  ", black, white, true, "  "},
+		{"aa", white, white, false, "\x1b[38;2;255;255;255maa\x1b[0m"},
+	}
+
+	for i, tt := range tests {
+		opts := &GradientOptions{SkipSpace: tt.skipSpace}
+		if got := GradientWith(tt.s, tt.from, tt.to, opts); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+	}
+
+	NoColour = true
+	defer func() { NoColour = false }()
+	if got := Gradient("abc", black, white); got != "abc" {
+		t.Errorf("want: %q, got: %q", "abc", got)
+	}
+}
+
+func TestRainbow(t *testing.T) {
+	NoColour = false
+	defer SetLevel(GetLevel())
+	SetLevel(LevelTrueColour)
+
+	tests := []struct {
+		got, want string
+	}{
+		{Rainbow(""), ""},
+		{Rainbow("abc"), "\x1b[38;2;255;0;0ma\x1b[38;2;0;255;0mb\x1b[38;2;0;0;255mc\x1b[0m"},
+		{RainbowWith("ab", 1, 1, 60), "\x1b[38;2;255;255;0ma\x1b[38;2;0;0;255mb\x1b[0m"},
+		{RainbowWith("a", 0, 0.5, 0), "\x1b[38;2;128;128;128ma\x1b[0m"},
+	}
+
+	for i, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, tt.got)
+		}
+	}
+
+	NoColour = true
+	defer func() { NoColour = false }()
+	if got := Rainbow("abc"); got != "abc" {
+		t.Errorf("want: %q, got: %q", "abc", got)
+	}
+}
@@ -0,0 +1,216 @@
+package colour
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Rule colours every match of Pattern with Colour.
+type Rule struct {
+	Pattern *regexp.Regexp
+	Colour  *Colour
+}
+
+// HighlightWriter colours the parts of its input matching a set of rules
+// before passing it on, for example to highlight errors while tailing a log.
+// Input is processed a line at a time, so matches are found even when a line
+// arrives over several writes. Call Flush to write a final line that is not
+// terminated by a newline.
+type HighlightWriter struct {
+	mu    sync.Mutex
+	w     io.Writer
+	rules []Rule
+	buf   []byte
+}
+
+// NewHighlightWriter returns a HighlightWriter applying rules to everything
+// written to it before writing it to w. Where matches of several rules
+// overlap, the rule listed first wins for the overlapping text.
+func NewHighlightWriter(w io.Writer, rules []Rule) *HighlightWriter {
+	return &HighlightWriter{w: w, rules: rules}
+}
+
+// Write highlights each complete line in p and writes it to the underlying
+// writer. A trailing partial line is kept until it is completed by a later
+// write or Flush is called.
+func (hw *HighlightWriter) Write(p []byte) (int, error) {
+	hw.mu.Lock()
+	defer hw.mu.Unlock()
+
+	hw.buf = append(hw.buf, p...)
+	i := bytes.LastIndexByte(hw.buf, '\n')
+	if i < 0 {
+		return len(p), nil
+	}
+
+	var b strings.Builder
+	for _, line := range strings.SplitAfter(string(hw.buf[:i+1]), "\n") {
+		if line == "" {
+			continue
+		}
+		b.WriteString(hw.highlight(strings.TrimSuffix(line, "\n")))
+		b.WriteByte('\n')
+	}
+	hw.buf = append(hw.buf[:0], hw.buf[i+1:]...)
+
+	if _, err := io.WriteString(hw.w, b.String()); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Flush highlights and writes any buffered partial line.
+func (hw *HighlightWriter) Flush() error {
+	hw.mu.Lock()
+	defer hw.mu.Unlock()
+
+	if len(hw.buf) == 0 {
+		return nil
+	}
+
+	s := hw.highlight(string(hw.buf))
+	hw.buf = hw.buf[:0]
+	_, err := io.WriteString(hw.w, s)
+	return err
+}
+
+// highlight colours the matches in a single line. Rules are matched against
+// the visible text, so escape sequences already in the line are neither
+// matched nor split. Each byte is assigned the colour of the first rule
+// matching it, and runs of bytes sharing a colour are wrapped together, so
+// overlapping matches never nest or cut into each other's sequences.
+func (hw *HighlightWriter) highlight(line string) string {
+	line = sanitize(line)
+	v, pos := visibleText(line)
+
+	var owner []*Colour
+	for _, r := range hw.rules {
+		if r.Pattern == nil || r.Colour == nil {
+			continue
+		}
+		for _, m := range r.Pattern.FindAllStringIndex(v, -1) {
+			if owner == nil {
+				owner = make([]*Colour, len(v))
+			}
+			for i := m[0]; i < m[1]; i++ {
+				if owner[i] == nil {
+					owner[i] = r.Colour
+				}
+			}
+		}
+	}
+	if owner == nil {
+		return line
+	}
+
+	return colourVisible(line, pos, owner)
+}
+
+// colourVisible wraps each run of visible bytes of s sharing a colour in
+// owner, which holds the colour of every byte of the visible text or nil to
+// leave it plain, and pos their offsets in s as returned by visibleText.
+// Escape sequences in s are copied as they are, so a run is wrapped in parts
+// where it spans one, and the style in effect before a run is reset before it
+// and opened again after it.
+func colourVisible(s string, pos []int, owner []*Colour) string {
+	var b strings.Builder
+	var st sgrState
+	last := 0
+	for i := 0; i < len(pos); {
+		c := owner[i]
+		j := i + 1
+		for j < len(pos) && owner[j] == c && pos[j] == pos[j-1]+1 {
+			j++
+		}
+		if c == nil {
+			i = j
+			continue
+		}
+
+		start, end := pos[i], pos[j-1]+1
+		b.WriteString(s[last:start])
+		st.feed(s[last:start])
+
+		prefix, suffix := c.affixes()
+		if prefix != "" && st.active != "" {
+			b.WriteString(resetSequence)
+		}
+		b.WriteString(prefix)
+		b.WriteString(s[start:end])
+		b.WriteString(suffix)
+		if suffix != "" {
+			b.WriteString(st.active)
+		}
+
+		last = end
+		i = j
+	}
+	b.WriteString(s[last:])
+
+	return b.String()
+}
+
+// Highlight returns s with every occurrence of substr in its visible text
+// coloured with c and the rest left as it is, for example to highlight search
+// terms. Escape sequences already in s are neither matched nor split.
+// Overlapping and adjacent occurrences are coloured as a single span, and
+// each span is followed by a reset. s is returned unchanged if substr is
+// empty.
+func (c *Colour) Highlight(s, substr string) string {
+	s = sanitize(s)
+	if substr == "" {
+		return s
+	}
+
+	v, pos := visibleText(s)
+
+	var spans [][]int
+	for i := 0; ; {
+		j := strings.Index(v[i:], substr)
+		if j < 0 {
+			break
+		}
+		spans = append(spans, []int{i + j, i + j + len(substr)})
+		// continue after the first rune of the match to find overlaps
+		_, size := utf8.DecodeRuneInString(v[i+j:])
+		i += j + size
+	}
+
+	return c.highlightSpans(s, v, pos, spans)
+}
+
+// HighlightRegexp is like Highlight but colours every match of re. Empty
+// matches are ignored.
+func (c *Colour) HighlightRegexp(s string, re *regexp.Regexp) string {
+	s = sanitize(s)
+	v, pos := visibleText(s)
+
+	return c.highlightSpans(s, v, pos, re.FindAllStringIndex(v, -1))
+}
+
+// highlightSpans colours the byte ranges of the visible text v of s given by
+// spans with c, where pos holds the offsets of v in s.
+func (c *Colour) highlightSpans(s, v string, pos []int, spans [][]int) string {
+	var owner []*Colour
+	for _, m := range spans {
+		if m[0] == m[1] {
+			continue
+		}
+		if owner == nil {
+			owner = make([]*Colour, len(v))
+		}
+		for i := m[0]; i < m[1]; i++ {
+			owner[i] = c
+		}
+	}
+	if owner == nil {
+		return s
+	}
+
+	return colourVisible(s, pos, owner)
+}
@@ -0,0 +1,129 @@
+package colour
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+)
+
+func TestHighlightWriter(t *testing.T) {
+	NoColour = false
+
+	red := New(FgRed)
+	green := New(FgGreen)
+	rules := []Rule{
+		{regexp.MustCompile(`ERROR`), red},
+		{regexp.MustCompile(`[A-Z]+`), green},
+	}
+
+	tests := []struct {
+		writes []string
+		want   string
+	}{
+		{[]string{"plain\n"}, "plain\n"},
+		{[]string{"an ERROR here\n"}, "an \x1b[31mERROR\x1b[0m here\n"},
+		// match spanning two writes
+		{[]string{"an ERR", "OR here\n"}, "an \x1b[31mERROR\x1b[0m here\n"},
+		// overlapping matches, the first rule wins
+		{[]string{"XERRORS\n"}, "\x1b[32mX\x1b[0m\x1b[31mERROR\x1b[0m\x1b[32mS\x1b[0m\n"},
+		{[]string{"a\nERROR\nb"}, "a\n\x1b[31mERROR\x1b[0m\nb"},
+		{[]string{"OK"}, "\x1b[32mOK\x1b[0m"},
+	}
+
+	for i, tt := range tests {
+		var buf bytes.Buffer
+		hw := NewHighlightWriter(&buf, rules)
+		for _, w := range tt.writes {
+			if _, err := hw.Write([]byte(w)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := hw.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+	}
+}
+
+func TestHighlightWriterColoured(t *testing.T) {
+	NoColour = false
+
+	rules := []Rule{
+		{regexp.MustCompile(`\d+`), New(FgBlue)},
+		{regexp.MustCompile(`ERROR`), New(FgRed)},
+	}
+
+	tests := []struct {
+		in, want string
+	}{
+		// the parameters of existing sequences are not matched
+		{"\x1b[1mcode\x1b[0m 7", "\x1b[1mcode\x1b[0m \x1b[34m7\x1b[0m"},
+		// the style around a match is opened again after it
+		{"\x1b[33mwarn 42\x1b[0m", "\x1b[33mwarn \x1b[0m\x1b[34m42\x1b[0m\x1b[33m\x1b[0m"},
+		// a match spanning a sequence is wrapped on both sides of it
+		{"ER\x1b[1mROR", "\x1b[31mER\x1b[0m\x1b[1m\x1b[0m\x1b[31mROR\x1b[0m\x1b[1m"},
+	}
+
+	for i, tt := range tests {
+		var buf bytes.Buffer
+		hw := NewHighlightWriter(&buf, rules)
+		if _, err := hw.Write([]byte(tt.in)); err != nil {
+			t.Fatal(err)
+		}
+		if err := hw.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	NoColour = false
+
+	red := New(FgRed)
+	tests := []struct {
+		s, substr string
+		want      string
+	}{
+		{"a needle in a haystack", "needle", "a \x1b[31mneedle\x1b[0m in a haystack"},
+		{"go go", "go", "\x1b[31mgo\x1b[0m \x1b[31mgo\x1b[0m"},
+		{"aaaa b", "aaa", "\x1b[31maaaa\x1b[0m b"},
+		{"abab", "ab", "\x1b[31mabab\x1b[0m"},
+		{"grüße grüße", "ü", "gr\x1b[31mü\x1b[0mße gr\x1b[31mü\x1b[0mße"},
+		{"nothing", "x", "nothing"},
+		{"empty", "", "empty"},
+		// matched in the visible text only
+		{"\x1b[1mm\x1b[0m 1m", "1m", "\x1b[1mm\x1b[0m \x1b[31m1m\x1b[0m"},
+		{"\x1b[1mne\x1b[0medle", "needle", "\x1b[1m\x1b[0m\x1b[31mne\x1b[0m\x1b[1m\x1b[0m\x1b[31medle\x1b[0m"},
+	}
+
+	for i, tt := range tests {
+		if got := red.Highlight(tt.s, tt.substr); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+	}
+}
+
+func TestHighlightRegexp(t *testing.T) {
+	NoColour = false
+
+	red := New(FgRed)
+	tests := []struct {
+		s, re string
+		want  string
+	}{
+		{"id 12 and 345", `\d+`, "id \x1b[31m12\x1b[0m and \x1b[31m345\x1b[0m"},
+		{"abc", `x*`, "abc"},
+		{"\x1b[32mok 7\x1b[0m", `\d+`, "\x1b[32mok \x1b[0m\x1b[31m7\x1b[0m\x1b[32m\x1b[0m"},
+	}
+
+	for i, tt := range tests {
+		if got := red.HighlightRegexp(tt.s, regexp.MustCompile(tt.re)); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+	}
+}
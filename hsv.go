@@ -0,0 +1,91 @@
+package colour
+
+import "math"
+
+// HSV returns a new colour object with a truecolour foreground given by hue
+// in degrees, saturation and value. Hues outside [0, 360) wrap around and
+// saturation and value are clamped to [0, 1].
+//
+// The conversion follows the usual formulas: with chroma C = V × S, the hue
+// picks one of six sectors of the colour wheel in which the largest RGB
+// component is C, the smallest 0 and the remaining one
+// X = C × (1 - |(H / 60° mod 2) - 1|), after which V - C is added to all three.
+func HSV(h, s, v float64) *Colour {
+	rgb := hsvToRGB(h, s, v)
+	return RGB(rgb[0], rgb[1], rgb[2])
+}
+
+// HSL returns a new colour object with a truecolour foreground given by hue
+// in degrees, saturation and lightness. Hues outside [0, 360) wrap around and
+// saturation and lightness are clamped to [0, 1].
+//
+// The conversion is that of HSV, except that the chroma is
+// C = (1 - |2L - 1|) × S and L - C / 2 is added to all three components.
+func HSL(h, s, l float64) *Colour {
+	rgb := hslToRGB(h, s, l)
+	return RGB(rgb[0], rgb[1], rgb[2])
+}
+
+// hsvToRGB converts a colour given by hue in degrees, saturation and value in
+// [0, 1] to RGB.
+func hsvToRGB(h, s, v float64) [3]uint8 {
+	s, v = clampUnit(s), clampUnit(v)
+	c := v * s
+	return chromaToRGB(h, c, v-c)
+}
+
+// hslToRGB converts a colour given by hue in degrees, saturation and
+// lightness in [0, 1] to RGB.
+func hslToRGB(h, s, l float64) [3]uint8 {
+	s, l = clampUnit(s), clampUnit(l)
+	c := (1 - math.Abs(2*l-1)) * s
+	return chromaToRGB(h, c, l-c/2)
+}
+
+// chromaToRGB returns the colour of hue h in degrees with chroma c, with m
+// added to each component.
+func chromaToRGB(h, c, m float64) [3]uint8 {
+	if math.IsNaN(h) || math.IsInf(h, 0) {
+		h = 0
+	}
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = c, x, 0
+	case h < 120:
+		r, g, b = x, c, 0
+	case h < 180:
+		r, g, b = 0, c, x
+	case h < 240:
+		r, g, b = 0, x, c
+	case h < 300:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+
+	return [3]uint8{
+		uint8(roundInt((r + m) * 255)),
+		uint8(roundInt((g + m) * 255)),
+		uint8(roundInt((b + m) * 255)),
+	}
+}
+
+// clampUnit limits f to [0, 1], treating NaN as 0.
+func clampUnit(f float64) float64 {
+	switch {
+	case f > 1:
+		return 1
+	case f >= 0:
+		return f
+	}
+
+	return 0
+}
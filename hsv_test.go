@@ -0,0 +1,71 @@
+package colour
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHSVToRGB(t *testing.T) {
+	tests := []struct {
+		h, s, v float64
+		want    [3]uint8
+	}{
+		{0, 0, 0, [3]uint8{0, 0, 0}},
+		{0, 0, 1, [3]uint8{255, 255, 255}},
+		{0, 1, 1, [3]uint8{255, 0, 0}},
+		{60, 1, 1, [3]uint8{255, 255, 0}},
+		{120, 1, 1, [3]uint8{0, 255, 0}},
+		{180, 1, 1, [3]uint8{0, 255, 255}},
+		{240, 1, 1, [3]uint8{0, 0, 255}},
+		{300, 1, 1, [3]uint8{255, 0, 255}},
+		{360, 1, 1, [3]uint8{255, 0, 0}},
+		{-120, 1, 1, [3]uint8{0, 0, 255}},
+		{30, 0.5, 0.5, [3]uint8{128, 96, 64}},
+	}
+
+	for i, tt := range tests {
+		if got := hsvToRGB(tt.h, tt.s, tt.v); got != tt.want {
+			t.Errorf("[%d] want: %v, got: %v", i, tt.want, got)
+		}
+	}
+}
+
+func TestHSLToRGB(t *testing.T) {
+	tests := []struct {
+		h, s, l float64
+		want    [3]uint8
+	}{
+		{0, 0, 0, [3]uint8{0, 0, 0}},
+		{0, 0, 1, [3]uint8{255, 255, 255}},
+		{0, 1, 0.5, [3]uint8{255, 0, 0}},
+		{120, 1, 0.25, [3]uint8{0, 128, 0}},
+		{240, 1, 0.75, [3]uint8{128, 128, 255}},
+		{210, 0.5, 0.5, [3]uint8{64, 128, 191}},
+		{0, 0, 0.5, [3]uint8{128, 128, 128}},
+	}
+
+	for i, tt := range tests {
+		if got := hslToRGB(tt.h, tt.s, tt.l); got != tt.want {
+			t.Errorf("[%d] want: %v, got: %v", i, tt.want, got)
+		}
+	}
+}
+
+func TestHSLAndHSV(t *testing.T) {
+	tests := []struct {
+		c, want *Colour
+	}{
+		{HSV(0, 1, 1), RGB(255, 0, 0)},
+		{HSV(480, 2, 1), RGB(0, 255, 0)},
+		{HSV(math.NaN(), -1, 1), RGB(255, 255, 255)},
+		{HSL(240, 1, 0.5), RGB(0, 0, 255)},
+		{HSL(-60, 1, 0.5), RGB(255, 0, 255)},
+		{HSL(0, 0.5, 5), RGB(255, 255, 255)},
+	}
+
+	for i, tt := range tests {
+		if !tt.c.Equals(tt.want) {
+			t.Errorf("[%d] want: %v, got: %v", i, tt.want.Attributes(), tt.c.Attributes())
+		}
+	}
+}
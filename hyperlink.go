@@ -0,0 +1,63 @@
+package colour
+
+import "strings"
+
+var (
+	// NoHyperlinks disables terminal hyperlinks, for terminals that print
+	// the OSC 8 sequences instead of ignoring them. Links are also disabled
+	// whenever NoColour is set.
+	NoHyperlinks = false
+
+	// ShowHyperlinkURLs makes links fall back to "text (url)" rather than
+	// just their text when hyperlinks are disabled, so the target is not
+	// lost.
+	ShowHyperlinkURLs = false
+)
+
+// Sequences opening and closing an OSC 8 hyperlink.
+const (
+	hyperlinkOpen  = escape + "]8;;"
+	hyperlinkClose = escape + "]8;;" + escape + "\\"
+)
+
+// Hyperlink returns text as a link to url, which terminals supporting OSC 8
+// render as clickable. When hyperlinks are disabled the plain text is
+// returned instead. Control characters such as ESC and BEL are always removed
+// from url, as they would end the sequence early, and text is sanitized if
+// SetGlobalSanitize is enabled.
+func Hyperlink(url, text string) string {
+	return hyperlink(url, sanitize(text))
+}
+
+// Hyperlink is like the Hyperlink function but colours the text of the link.
+func (c *Colour) Hyperlink(url, text string) string {
+	return hyperlink(url, c.wrap(text))
+}
+
+// hyperlink is like Hyperlink but takes text as it is.
+func hyperlink(url, text string) string {
+	url = stripControls(url)
+	if GetNoColour() || NoHyperlinks {
+		return linkFallback(url, text)
+	}
+
+	return hyperlinkOpen + url + escape + "\\" + text + hyperlinkClose
+}
+
+func linkFallback(url, text string) string {
+	if ShowHyperlinkURLs {
+		return text + " (" + url + ")"
+	}
+
+	return text
+}
+
+// stripControls removes the C0 and C1 control characters and DEL from s.
+func stripControls(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < ' ' || r == 0x7f || isC1(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
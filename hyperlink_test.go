@@ -0,0 +1,85 @@
+package colour
+
+import "testing"
+
+func TestHyperlink(t *testing.T) {
+	NoColour = false
+	red := New(FgRed)
+
+	tests := []struct {
+		got, want string
+	}{
+		{
+			Hyperlink("http://example.com", "example"),
+			"\x1b]8;;http://example.com\x1b\\example\x1b]8;;\x1b\\",
+		},
+		{
+			red.Hyperlink("http://example.com", "example"),
+			"\x1b]8;;http://example.com\x1b\\\x1b[31mexample\x1b[0m\x1b]8;;\x1b\\",
+		},
+	}
+
+	for i, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, tt.got)
+		}
+	}
+
+	NoHyperlinks = true
+	defer func() { NoHyperlinks = false }()
+
+	if got, want := Hyperlink("http://example.com", "example"), "example"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+	if got, want := red.Hyperlink("http://example.com", "example"), "\x1b[31mexample\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+
+	ShowHyperlinkURLs = true
+	defer func() { ShowHyperlinkURLs = false }()
+
+	if got, want := Hyperlink("http://example.com", "example"), "example (http://example.com)"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+
+	NoHyperlinks = false
+	NoColour = true
+	defer func() { NoColour = false }()
+
+	if got, want := red.Hyperlink("http://example.com", "example"), "example (http://example.com)"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+}
+
+func TestHyperlinkControls(t *testing.T) {
+	NoColour = false
+	red := New(FgRed)
+
+	url := "http://example.com/\x1b\\\x1b[2J\x07\u009b"
+	want := "\x1b]8;;http://example.com/\\[2J\x1b\\example\x1b]8;;\x1b\\"
+	if got := Hyperlink(url, "example"); got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+
+	SetGlobalSanitize(true)
+	defer SetGlobalSanitize(false)
+
+	tests := []struct {
+		got, want string
+	}{
+		{
+			Hyperlink("http://example.com", "ex\x1b]8;;http://evil\x1b\\ample"),
+			"\x1b]8;;http://example.com\x1b\\example\x1b]8;;\x1b\\",
+		},
+		{
+			red.Hyperlink("http://example.com", "ex\x1b[2Jample"),
+			"\x1b]8;;http://example.com\x1b\\\x1b[31mexample\x1b[0m\x1b]8;;\x1b\\",
+		},
+	}
+
+	for i, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, tt.got)
+		}
+	}
+}
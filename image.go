@@ -0,0 +1,14 @@
+package colour
+
+// ImageFallback returns a placeholder for an inline image that cannot be
+// displayed, such as "[image: logo]" for the alt text "logo", styled with c.
+// An empty alt text gives "[image]". The placeholder is plain when c is nil or
+// colour is disabled.
+func ImageFallback(alt string, c *Colour) string {
+	s := "[image]"
+	if alt != "" {
+		s = "[image: " + alt + "]"
+	}
+
+	return styled(c, s)
+}
@@ -0,0 +1,30 @@
+package colour
+
+import "testing"
+
+func TestImageFallback(t *testing.T) {
+	NoColour = false
+	c := New(Faint, Italic)
+
+	tests := []struct {
+		alt  string
+		c    *Colour
+		want string
+	}{
+		{"logo", nil, "[image: logo]"},
+		{"", nil, "[image]"},
+		{"logo", c, "\x1b[2;3m[image: logo]\x1b[0m"},
+	}
+
+	for i, tt := range tests {
+		if got := ImageFallback(tt.alt, tt.c); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+	}
+
+	NoColour = true
+	defer func() { NoColour = false }()
+	if got, want := ImageFallback("logo", c), "[image: logo]"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+}
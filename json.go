@@ -0,0 +1,75 @@
+package colour
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// attributeValues maps the names of attributes back to their values.
+var attributeValues = func() map[string]Attribute {
+	m := make(map[string]Attribute, len(attributeNames))
+	for a, name := range attributeNames {
+		m[name] = a
+	}
+	return m
+}()
+
+// MarshalJSON encodes the colour as an array of strings, one per attribute in
+// the order they were added. Attributes with a constant are given by its
+// name, such as "FgRed", others by their SGR parameters, such as "38;5;196"
+// for an extended colour or "200" for an unknown attribute:
+//
+//	["Bold", "FgRed", "48;2;0;0;128"]
+//
+// Whether colour is disabled for the colour is not encoded.
+func (c *Colour) MarshalJSON() ([]byte, error) {
+	groups := c.groups()
+	attrs := make([]string, 0, len(groups))
+	for _, g := range groups {
+		if len(g) == 1 {
+			if name, ok := attributeNames[g[0]]; ok {
+				attrs = append(attrs, name)
+				continue
+			}
+		}
+
+		params := make([]string, len(g))
+		for i, a := range g {
+			params[i] = attrParam(a)
+		}
+		attrs = append(attrs, strings.Join(params, ";"))
+	}
+
+	return json.Marshal(attrs)
+}
+
+// UnmarshalJSON decodes a colour encoded by MarshalJSON, replacing the
+// attributes of c. An error is returned for names and parameters that are
+// not valid.
+func (c *Colour) UnmarshalJSON(b []byte) error {
+	var attrs []string
+	if err := json.Unmarshal(b, &attrs); err != nil {
+		return err
+	}
+
+	params := make([]param, 0, len(attrs))
+	for _, s := range attrs {
+		if a, ok := attributeValues[s]; ok {
+			params = append(params, basicParam(a))
+			continue
+		}
+
+		p, err := parseSGR(s)
+		if s == "" || err != nil {
+			return fmt.Errorf("colour: unknown attribute %q", s)
+		}
+		params = append(params, p.params...)
+	}
+	c.mu.Lock()
+	c.params = params
+	c.gen++
+	c.mu.Unlock()
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package colour
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestColourJSON(t *testing.T) {
+	tests := []struct {
+		c    *Colour
+		want string
+	}{
+		{New(), `[]`},
+		{New(Bold, FgRed), `["Bold","FgRed"]`},
+		{NewFg256(196).AddBgRGB(0, 0, 128), `["38;5;196","48;2;0;0;128"]`},
+		{New(CurlyUnderline, 200).UnderlineColour256(1), `["CurlyUnderline","200","58;5;1"]`},
+	}
+
+	for i, tt := range tests {
+		b, err := json.Marshal(tt.c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(b); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+
+		got := New(Italic)
+		if err := json.Unmarshal(b, got); err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equals(tt.c) {
+			t.Errorf("[%d] %s did not round trip", i, b)
+		}
+	}
+
+	// colours inside other values
+	theme := map[string]*Colour{"error": New(FgRed, Bold)}
+	b, err := json.Marshal(theme)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), `{"error":["FgRed","Bold"]}`; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+}
+
+func TestColourUnmarshalJSONErrors(t *testing.T) {
+	tests := []string{
+		`"FgRed"`,
+		`[1]`,
+		`["FgPurple"]`,
+		`[""]`,
+		`["38;5"]`,
+	}
+
+	for i, s := range tests {
+		var c Colour
+		if err := json.Unmarshal([]byte(s), &c); err == nil {
+			t.Errorf("[%d] %s: want error", i, s)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package colour
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// KV returns key=value in logfmt style, with the key and value coloured
+// independently and the "=" left plain. The value is quoted when logfmt
+// requires it, for example when it contains spaces. Either colour may be nil
+// to leave that part unstyled.
+func KV(key string, value interface{}, keyColour, valColour *Colour) string {
+	v := fmt.Sprint(value)
+	if needsQuote(v) {
+		v = strconv.Quote(v)
+	}
+
+	return styled(keyColour, key) + "=" + styled(valColour, v)
+}
+
+// needsQuote reports whether a logfmt value must be quoted.
+func needsQuote(s string) bool {
+	if s == "" {
+		return true
+	}
+
+	return strings.IndexFunc(s, func(r rune) bool {
+		return r <= ' ' || r == '=' || r == '"' || r == 0x7f
+	}) >= 0
+}
+
+// styled wraps s with c, leaving it plain if c is nil. s is sanitized either
+// way.
+func styled(c *Colour, s string) string {
+	if c == nil {
+		return sanitize(s)
+	}
+
+	return c.wrap(s)
+}
@@ -0,0 +1,35 @@
+package colour
+
+import "testing"
+
+func TestKV(t *testing.T) {
+	NoColour = false
+	key := New(FgBlue)
+	val := New(FgGreen, Bold)
+
+	tests := []struct {
+		key        string
+		value      interface{}
+		keyC, valC *Colour
+		want       string
+	}{
+		{"a", 1, nil, nil, "a=1"},
+		{"a", "b c", nil, nil, `a="b c"`},
+		{"a", "", nil, nil, `a=""`},
+		{"a", `x="y"`, nil, nil, `a="x=\"y\""`},
+		{"a", "b", key, nil, "\x1b[34ma\x1b[0m=b"},
+		{"a", "b c", key, val, "\x1b[34ma\x1b[0m=\x1b[32;1m\"b c\"\x1b[0m"},
+	}
+
+	for i, tt := range tests {
+		if got := KV(tt.key, tt.value, tt.keyC, tt.valC); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+	}
+
+	NoColour = true
+	defer func() { NoColour = false }()
+	if got := KV("a", 1, key, val); got != "a=1" {
+		t.Errorf("want: %q, got: %q", "a=1", got)
+	}
+}
@@ -0,0 +1,77 @@
+package colour
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// legendWidth is the line width a legend is laid out in.
+const legendWidth = 80
+
+// Swatch returns a small block showing c, for use in legends and keys.
+// Colours with a background are shown as blank cells in that background,
+// others as solid blocks in their foreground. It returns an empty string if c
+// is nil or colour is disabled for it.
+func Swatch(c *Colour) string {
+	if c == nil || c.isNoColourSet() {
+		return ""
+	}
+
+	for _, g := range c.groups() {
+		if isBackground(g) {
+			return c.wrap("  ")
+		}
+	}
+
+	return c.wrap("██")
+}
+
+// Legend writes a key explaining what each colour means, with a swatch
+// followed by its label. Entries are sorted by label and laid out in as many
+// columns as fit in 80 cells. Entries with a nil colour, and all of them when
+// colour is disabled, are written as their label only.
+func Legend(entries map[string]*Colour, w io.Writer) {
+	labels := make([]string, 0, len(entries))
+	for label := range entries {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	cells := make([]string, len(labels))
+	width := 0
+	for i, label := range labels {
+		if s := Swatch(entries[label]); s != "" {
+			cells[i] = s + " " + label
+		} else {
+			cells[i] = label
+		}
+		if n := visibleWidth(cells[i]); n > width {
+			width = n
+		}
+	}
+
+	const gap = "  "
+	perRow := (legendWidth + len(gap)) / (width + len(gap))
+	if perRow < 1 {
+		perRow = 1
+	}
+
+	for i := 0; i < len(cells); i += perRow {
+		row := cells[i:]
+		if len(row) > perRow {
+			row = row[:perRow]
+		}
+
+		line := make([]string, len(row))
+		for j, cell := range row {
+			if j == len(row)-1 {
+				line[j] = cell
+				continue
+			}
+			line[j] = Columns(cell, []int{width})[0]
+		}
+		fmt.Fprintln(w, strings.Join(line, gap))
+	}
+}
@@ -0,0 +1,71 @@
+package colour
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSwatch(t *testing.T) {
+	NoColour = false
+
+	if got, want := Swatch(New(FgRed)), "\x1b[31m██\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+	if got, want := Swatch(New(FgRed, BgHiBlue)), "\x1b[31;104m  \x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+
+	c := New(FgRed)
+	c.DisableColour()
+	if got := Swatch(c); got != "" {
+		t.Errorf("want: %q, got: %q", "", got)
+	}
+	if got := Swatch(nil); got != "" {
+		t.Errorf("want: %q, got: %q", "", got)
+	}
+}
+
+func TestLegend(t *testing.T) {
+	NoColour = false
+
+	var buf bytes.Buffer
+	Legend(map[string]*Colour{
+		"pass":    New(FgGreen),
+		"fail":    New(FgRed),
+		"skipped": New(FgYellow),
+	}, &buf)
+
+	want := "\x1b[31m██\x1b[0m fail     " +
+		"\x1b[32m██\x1b[0m pass     " +
+		"\x1b[33m██\x1b[0m skipped\n"
+	if got := buf.String(); got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+
+	// nil colours are plain
+	buf.Reset()
+	Legend(map[string]*Colour{"pass": New(FgGreen), "other": nil}, &buf)
+	if got, want := buf.String(), "other    \x1b[32m██\x1b[0m pass\n"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+
+	// wraps onto several rows
+	entries := make(map[string]*Colour)
+	for _, l := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"} {
+		entries[strings.Repeat(l, 10)] = New(FgBlue)
+	}
+	buf.Reset()
+	Legend(entries, &buf)
+	if n := strings.Count(buf.String(), "\n"); n != 2 {
+		t.Errorf("want 2 rows, got %d: %q", n, buf.String())
+	}
+
+	NoColour = true
+	defer func() { NoColour = false }()
+	buf.Reset()
+	Legend(map[string]*Colour{"pass": New(FgGreen), "fail": New(FgRed)}, &buf)
+	if got, want := buf.String(), "fail  pass\n"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+}
@@ -0,0 +1,218 @@
+package colour
+
+import (
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Level describes the number of colours a terminal is able to display. The
+// value of each level is its number of colours.
+type Level int
+
+// Colour levels. At LevelNone colour is disabled as if NoColour was set,
+// except for colours enabled with EnableColour.
+const (
+	LevelNone       Level = 0
+	Level16         Level = 16
+	Level256        Level = 256
+	LevelTrueColour Level = 1 << 24
+)
+
+var (
+	levelOnce sync.Once
+	level     int32 // the current Level, valid once levelOnce is done
+	maxLevel  = int32(LevelTrueColour)
+
+	detector   func() int // set by SetDetector, DetectLevel is used if nil
+	detectorMu sync.Mutex // protects detector
+)
+
+// GetLevel returns the colour level output is rendered for. It is detected
+// with DetectLevel, or the detector given to SetDetector, on first use unless
+// set with SetLevel, and capped by SetMaxLevel. Colours the level cannot
+// display are replaced with the nearest ones it can.
+func GetLevel() Level {
+	levelOnce.Do(func() {
+		atomic.StoreInt32(&level, int32(detectLevel()))
+	})
+
+	l := atomic.LoadInt32(&level)
+	if max := atomic.LoadInt32(&maxLevel); l > max {
+		return Level(max)
+	}
+
+	return Level(l)
+}
+
+// SetLevel overrides the detected colour level, for example in tests. A cap
+// set with SetMaxLevel still applies.
+func SetLevel(l Level) {
+	levelOnce.Do(func() {})
+	atomic.StoreInt32(&level, int32(l))
+}
+
+// SetMaxLevel caps the colour level output is rendered for, whether detected
+// or set with SetLevel, for example to limit recordings of terminal sessions
+// to 16 colours. Higher fidelity colours are then replaced with the nearest
+// ones the cap allows. The cap is LevelTrueColour by default, which leaves
+// every level as it is, and it can be raised again at any time.
+func SetMaxLevel(l Level) {
+	atomic.StoreInt32(&maxLevel, int32(l))
+}
+
+// GetMaxLevel returns the cap set with SetMaxLevel.
+func GetMaxLevel() Level {
+	return Level(atomic.LoadInt32(&maxLevel))
+}
+
+// SetDetector replaces the built-in detection of the colour level with f,
+// for embedders knowing their terminal better than the environment tells.
+// f returns the number of colours the terminal displays, such as 16, 256 or
+// 16777216 for truecolour, which is mapped to the highest level it can
+// represent. The level is detected again with f right away, replacing one set
+// with SetLevel. A nil f restores the detection of DetectLevel.
+func SetDetector(f func() int) {
+	detectorMu.Lock()
+	detector = f
+	detectorMu.Unlock()
+
+	SetLevel(detectLevel())
+}
+
+// detectLevel detects the colour level with the detector set with
+// SetDetector, or DetectLevel if there is none.
+func detectLevel() Level {
+	detectorMu.Lock()
+	f := detector
+	detectorMu.Unlock()
+
+	if f == nil {
+		return DetectLevel()
+	}
+
+	return levelFromColours(f())
+}
+
+// TerminalColours returns the number of colours output is rendered for: 0,
+// 16, 256 or 16777216 for truecolour. It is the colour level of GetLevel, so
+// it is detected from TERM, COLORTERM and terminfo once and can be overridden
+// with SetLevel.
+func TerminalColours() int {
+	return int(GetLevel())
+}
+
+// String returns the name of the level.
+func (l Level) String() string {
+	switch l {
+	case LevelNone:
+		return "none"
+	case Level16:
+		return "16"
+	case Level256:
+		return "256"
+	case LevelTrueColour:
+		return "truecolour"
+	}
+
+	return "Level(" + strconv.Itoa(int(l)) + ")"
+}
+
+// levelFromColours maps a number of colours to the highest level it can
+// represent. Terminals with 8 or 88 colours can only be relied upon for the
+// basic colours.
+func levelFromColours(n int) Level {
+	switch {
+	case n >= int(LevelTrueColour):
+		return LevelTrueColour
+	case n >= int(Level256):
+		return Level256
+	case n >= 8:
+		return Level16
+	}
+
+	return LevelNone
+}
+
+// DetectLevel returns the colour level of the terminal described by the
+// environment. A level given by FORCE_COLOR (1, 2 or 3 for 16, 256 or
+// truecolour) is used as is. Otherwise COLORTERM advertising truecolour wins,
+// as terminfo has no standard way to say so, then the colors capability of the
+// terminfo entry for TERM is used when one is installed, with no colour for
+// entries lacking it, falling back to guessing from TERM itself.
+func DetectLevel() Level {
+	on, forced, ok := forceColourLevel()
+	if ok && forced != LevelNone {
+		return forced
+	}
+
+	l := termLevel()
+	if on && l < Level16 {
+		return Level16
+	}
+
+	return l
+}
+
+// termLevel returns the colour level of the terminal named by TERM.
+func termLevel() Level {
+	term := os.Getenv("TERM")
+	if term == "dumb" {
+		return LevelNone
+	}
+
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return LevelTrueColour
+	}
+
+	if n, ok := terminfoColours(term); ok {
+		return levelFromColours(n)
+	}
+
+	return envLevel(term)
+}
+
+// forceColourLevel parses the FORCE_COLOR environment variable. It reports
+// whether colour is forced on or off, the level requested if any and whether
+// the variable is set at all. "0" and "false" force colour off, "1", "2" and
+// "3" force it on at 16, 256 or truecolour, and any other value forces it on
+// at the detected level.
+func forceColourLevel() (on bool, level Level, ok bool) {
+	v, ok := os.LookupEnv("FORCE_COLOR")
+	if !ok {
+		return false, LevelNone, false
+	}
+
+	switch strings.ToLower(v) {
+	case "0", "false":
+		return false, LevelNone, true
+	case "1":
+		return true, Level16, true
+	case "2":
+		return true, Level256, true
+	case "3":
+		return true, LevelTrueColour, true
+	}
+
+	return true, LevelNone, true
+}
+
+// envLevel guesses the colour level from the name of the terminal.
+func envLevel(term string) Level {
+	switch {
+	case strings.HasSuffix(term, "-direct"):
+		return LevelTrueColour
+	case strings.Contains(term, "256color"):
+		return Level256
+	case term != "":
+		return Level16
+	case runtime.GOOS == "windows":
+		return Level16
+	}
+
+	return LevelNone
+}
@@ -0,0 +1,287 @@
+package colour
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setenv sets or, given a nil value, unsets an environment variable and
+// returns a function restoring its previous state.
+func setenv(key string, value *string) func() {
+	old, ok := os.LookupEnv(key)
+	if value == nil {
+		os.Unsetenv(key)
+	} else {
+		os.Setenv(key, *value)
+	}
+
+	return func() {
+		if ok {
+			os.Setenv(key, old)
+		} else {
+			os.Unsetenv(key)
+		}
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestDetectLevel(t *testing.T) {
+	dir, err := ioutil.TempDir("", "terminfo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	entries := map[string][]byte{
+		"t/test-8":     terminfoEntry(terminfoMagic, 8),
+		"t/test-88":    terminfoEntry(terminfoMagic, 88),
+		"t/test-256":   terminfoEntry(terminfoMagic, 256),
+		"t/test-16m":   terminfoEntry(terminfoMagic32, 1<<24),
+		"t/test-mono":  terminfoEntry(terminfoMagic, -1),
+		"74/test-hex":  terminfoEntry(terminfoMagic, 256),
+		"t/test-bogus": []byte("not terminfo"),
+	}
+	for name, b := range entries {
+		p := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(p), 0755)
+		if err := ioutil.WriteFile(p, b, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	defer setenv("TERMINFO", strPtr(dir))()
+	defer setenv("TERMINFO_DIRS", strPtr(dir))()
+	defer setenv("HOME", nil)()
+	defer setenv("FORCE_COLOR", nil)()
+
+	tests := []struct {
+		term, colorterm string
+		want            Level
+	}{
+		{"dumb", "", LevelNone},
+		{"dumb", "truecolor", LevelNone},
+		{"test-8", "", Level16},
+		{"test-88", "", Level16},
+		{"test-256", "", Level256},
+		{"test-16m", "", LevelTrueColour},
+		{"test-hex", "", Level256},
+		// an entry without the colors capability
+		{"test-mono", "", LevelNone},
+		{"test-mono", "truecolor", LevelTrueColour},
+		{"test-8", "truecolor", LevelTrueColour},
+		{"test-8", "24bit", LevelTrueColour},
+		// no usable terminfo entry, guessed from the name
+		{"test-bogus", "", Level16},
+		{"missing-256color", "", Level256},
+		{"missing-direct", "", LevelTrueColour},
+		{"missing", "", Level16},
+	}
+
+	for _, tt := range tests {
+		restoreTerm := setenv("TERM", strPtr(tt.term))
+		restoreColorterm := setenv("COLORTERM", strPtr(tt.colorterm))
+
+		if got := DetectLevel(); got != tt.want {
+			t.Errorf("TERM=%s COLORTERM=%s want: %s, got: %s", tt.term, tt.colorterm, tt.want, got)
+		}
+
+		restoreColorterm()
+		restoreTerm()
+	}
+}
+
+// terminfoEntry builds a compiled terminfo entry with the given colors
+// capability, or none if colours is -1. An odd number of booleans exercises
+// the alignment padding.
+func terminfoEntry(magic int, colours int) []byte {
+	names := "test|test terminal\x00"
+	numSize := 2
+	if magic == terminfoMagic32 {
+		numSize = 4
+	}
+
+	le16 := func(b []byte, v int) []byte {
+		return append(b, byte(v), byte(v>>8))
+	}
+
+	var b []byte
+	for _, v := range []int{magic, len(names), 3, terminfoColors + 2, 0, 0} {
+		b = le16(b, v)
+	}
+	b = append(b, names...)
+	b = append(b, 1, 0, 1)
+	if len(b)%2 != 0 {
+		b = append(b, 0)
+	}
+	for i := 0; i < terminfoColors+2; i++ {
+		v := -1
+		if i == terminfoColors {
+			v = colours
+		}
+		b = le16(b, v)
+		if numSize == 4 {
+			b = le16(b, v>>16)
+		}
+	}
+
+	return b
+}
+
+func TestDetectLevelForced(t *testing.T) {
+	defer setenv("TERM", strPtr("xterm"))()
+	defer setenv("COLORTERM", nil)()
+	defer setenv("TERMINFO", strPtr(os.DevNull))()
+	defer setenv("TERMINFO_DIRS", strPtr(os.DevNull))()
+	defer setenv("FORCE_COLOR", nil)()
+
+	tests := []struct {
+		term, force string
+		want        Level
+	}{
+		{"xterm", "1", Level16},
+		{"xterm", "2", Level256},
+		{"xterm", "3", LevelTrueColour},
+		{"xterm-256color", "1", Level16},
+		{"xterm-256color", "true", Level256},
+		{"dumb", "", Level16},
+		{"dumb", "0", LevelNone},
+	}
+
+	for _, tt := range tests {
+		os.Setenv("TERM", tt.term)
+		os.Setenv("FORCE_COLOR", tt.force)
+
+		if got := DetectLevel(); got != tt.want {
+			t.Errorf("TERM=%s FORCE_COLOR=%s want: %s, got: %s", tt.term, tt.force, tt.want, got)
+		}
+	}
+}
+
+func TestTerminalColours(t *testing.T) {
+	defer SetLevel(GetLevel())
+	defer setenv("TERMINFO", strPtr(""))()
+	defer setenv("TERMINFO_DIRS", strPtr(""))()
+	defer setenv("HOME", nil)()
+	defer setenv("FORCE_COLOR", nil)()
+
+	tests := []struct {
+		term, colorterm string
+		want            int
+	}{
+		{"dumb", "truecolor", 0},
+		{"missing", "", 16},
+		{"missing-256color", "", 256},
+		{"missing", "truecolor", 16777216},
+	}
+
+	for i, tt := range tests {
+		restoreTerm := setenv("TERM", strPtr(tt.term))
+		restoreColorterm := setenv("COLORTERM", strPtr(tt.colorterm))
+		SetLevel(DetectLevel())
+		if got := TerminalColours(); got != tt.want {
+			t.Errorf("[%d] want: %d, got: %d", i, tt.want, got)
+		}
+		restoreColorterm()
+		restoreTerm()
+	}
+
+	SetLevel(Level256)
+	if got := TerminalColours(); got != 256 {
+		t.Errorf("want: %d, got: %d", 256, got)
+	}
+}
+
+func TestSetMaxLevel(t *testing.T) {
+	NoColour = false
+	defer SetLevel(GetLevel())
+	defer SetMaxLevel(GetMaxLevel())
+
+	SetLevel(LevelTrueColour)
+	SetMaxLevel(Level16)
+	if got := GetLevel(); got != Level16 {
+		t.Errorf("want: %v, got: %v", Level16, got)
+	}
+	if got, want := RGB(255, 0, 0).Sprint("x"), "\x1b[91mx\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+
+	SetLevel(LevelNone)
+	if got := GetLevel(); got != LevelNone {
+		t.Errorf("want: %v, got: %v", LevelNone, got)
+	}
+
+	SetLevel(Level256)
+	SetMaxLevel(LevelTrueColour)
+	if got := GetLevel(); got != Level256 {
+		t.Errorf("want: %v, got: %v", Level256, got)
+	}
+}
+
+func TestLevelNoneDisablesColour(t *testing.T) {
+	NoColour = false
+	defer SetLevel(GetLevel())
+	defer SetMaxLevel(GetMaxLevel())
+	defer SetDetector(nil)
+
+	forced := New(FgRed)
+	forced.EnableColour()
+
+	tests := []struct {
+		name string
+		set  func()
+	}{
+		{"SetLevel", func() { SetLevel(LevelNone) }},
+		{"SetDetector", func() { SetDetector(func() int { return 0 }) }},
+		{"SetMaxLevel", func() { SetLevel(LevelTrueColour); SetMaxLevel(LevelNone) }},
+	}
+
+	for _, tt := range tests {
+		SetLevel(Level16)
+		SetMaxLevel(LevelTrueColour)
+		tt.set()
+
+		if got, want := New(FgRed).Sprint("x"), "x"; got != want {
+			t.Errorf("%s: want: %q, got: %q", tt.name, want, got)
+		}
+		if got, want := RGB(1, 2, 3).Sprint("x"), "x"; got != want {
+			t.Errorf("%s: want: %q, got: %q", tt.name, want, got)
+		}
+		if got, want := forced.Sprint("x"), "\x1b[31mx\x1b[0m"; got != want {
+			t.Errorf("%s: want: %q, got: %q", tt.name, want, got)
+		}
+	}
+}
+
+func TestSetDetector(t *testing.T) {
+	defer SetLevel(GetLevel())
+	defer SetDetector(nil)
+
+	tests := []struct {
+		colours int
+		want    Level
+	}{
+		{0, LevelNone},
+		{8, Level16},
+		{88, Level16},
+		{256, Level256},
+		{1 << 24, LevelTrueColour},
+	}
+
+	for i, tt := range tests {
+		n := tt.colours
+		SetDetector(func() int { return n })
+		if got := GetLevel(); got != tt.want {
+			t.Errorf("[%d] want: %v, got: %v", i, tt.want, got)
+		}
+	}
+
+	SetDetector(nil)
+	if got, want := GetLevel(), DetectLevel(); got != want {
+		t.Errorf("want: %v, got: %v", want, got)
+	}
+}
@@ -0,0 +1,61 @@
+package colour
+
+import (
+	"io"
+	"strings"
+	"sync"
+)
+
+// Line repaints a single line of output in place, such as a status or
+// progress line. Writes are skipped when the content has not changed, which
+// avoids flicker in repaint loops.
+type Line struct {
+	mu    sync.Mutex
+	w     io.Writer
+	last  string
+	width int
+	drawn bool
+}
+
+// NewLine returns a Line writing to w.
+func NewLine(w io.Writer) *Line {
+	return &Line{w: w}
+}
+
+// Update replaces the line with s styled by c, which may be nil for plain
+// text. Nothing is written if the result equals what was last written. The
+// line is rewritten with a carriage return and clear to end of line, or when
+// colour is disabled globally by overwriting the previous content with
+// spaces.
+func (l *Line) Update(c *Colour, s string) error {
+	content := styled(c, s)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.drawn && content == l.last {
+		return nil
+	}
+
+	var b strings.Builder
+	b.WriteByte('\r')
+	b.WriteString(content)
+	width := visibleWidth(content)
+	if l.drawn {
+		if GetNoColour() {
+			if n := l.width - width; n > 0 {
+				b.WriteString(strings.Repeat(" ", n))
+				b.WriteString(strings.Repeat("\b", n))
+			}
+		} else {
+			b.WriteString(clearToEOL)
+		}
+	}
+
+	if _, err := io.WriteString(l.w, b.String()); err != nil {
+		return err
+	}
+
+	l.last, l.width, l.drawn = content, width, true
+	return nil
+}
@@ -0,0 +1,47 @@
+package colour
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLineUpdate(t *testing.T) {
+	NoColour = false
+
+	var buf bytes.Buffer
+	l := NewLine(&buf)
+	red := New(FgRed)
+
+	steps := []struct {
+		c    *Colour
+		s    string
+		want string
+	}{
+		{nil, "10%", "\r10%"},
+		{nil, "10%", ""},
+		{red, "10%", "\r\x1b[31m10%\x1b[0m\x1b[K"},
+		{red, "10%", ""},
+		{nil, "100%", "\r100%\x1b[K"},
+	}
+
+	for i, step := range steps {
+		buf.Reset()
+		if err := l.Update(step.c, step.s); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != step.want {
+			t.Errorf("[%d] want: %q, got: %q", i, step.want, got)
+		}
+	}
+
+	NoColour = true
+	defer func() { NoColour = false }()
+
+	buf.Reset()
+	l = NewLine(&buf)
+	l.Update(red, "working")
+	l.Update(red, "done")
+	if got, want := buf.String(), "\rworking\rdone   \b\b\b"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+}
@@ -0,0 +1,155 @@
+package colour
+
+// basicPalette holds the usual xterm values of the 16 basic colours, in the
+// order of their SGR codes.
+var basicPalette = [16][3]uint8{
+	{0, 0, 0},
+	{205, 0, 0},
+	{0, 205, 0},
+	{205, 205, 0},
+	{0, 0, 238},
+	{205, 0, 205},
+	{0, 205, 205},
+	{229, 229, 229},
+	{127, 127, 127},
+	{255, 0, 0},
+	{0, 255, 0},
+	{255, 255, 0},
+	{92, 92, 255},
+	{255, 0, 255},
+	{0, 255, 255},
+	{255, 255, 255},
+}
+
+// cubeLevels are the component values of the 6x6x6 colour cube of the 256
+// colour palette.
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// paletteRGB returns the components of entry n of the 256 colour palette.
+func paletteRGB(n uint8) [3]uint8 {
+	switch {
+	case n < 16:
+		return basicPalette[n]
+	case n < 232:
+		n -= 16
+		return [3]uint8{cubeLevels[n/36], cubeLevels[n/6%6], cubeLevels[n%6]}
+	}
+
+	v := 8 + 10*(n-232)
+	return [3]uint8{v, v, v}
+}
+
+// distance returns the squared euclidean distance between two colours.
+func distance(a, b [3]uint8) int {
+	d := 0
+	for i := range a {
+		x := int(a[i]) - int(b[i])
+		d += x * x
+	}
+
+	return d
+}
+
+// nearestBasic returns the index of the basic colour closest to rgb.
+func nearestBasic(rgb [3]uint8) int {
+	best, bestDist := 0, -1
+	for i, p := range basicPalette {
+		if d := distance(rgb, p); bestDist < 0 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+
+	return best
+}
+
+// nearest256 returns the entry of the 256 colour palette closest to rgb,
+// considering the colour cube and the grey ramp.
+func nearest256(rgb [3]uint8) uint8 {
+	var cube [3]uint8
+	var idx int
+	for i, v := range rgb {
+		l := nearestCubeLevel(v)
+		cube[i] = cubeLevels[l]
+		idx = idx*6 + l
+	}
+
+	avg := (int(rgb[0]) + int(rgb[1]) + int(rgb[2])) / 3
+	grey := 0
+	if avg > 238 {
+		grey = 23
+	} else if avg > 8 {
+		grey = (avg - 3) / 10
+	}
+	gv := uint8(8 + 10*grey)
+
+	if distance(rgb, [3]uint8{gv, gv, gv}) < distance(rgb, cube) {
+		return uint8(232 + grey)
+	}
+
+	return uint8(16 + idx)
+}
+
+// nearestCubeLevel returns the index of the cube level closest to v.
+func nearestCubeLevel(v uint8) int {
+	best := 0
+	for i, l := range cubeLevels {
+		if absDiff(v, l) < absDiff(v, cubeLevels[best]) {
+			best = i
+		}
+	}
+
+	return best
+}
+
+func absDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+
+	return b - a
+}
+
+// downsample converts the parameter group g to the nearest equivalent the
+// given level can display. Groups other than extended colours are returned
+// unchanged. Underline colours have no basic form, so below 256 colours they
+// are given as one of the first 16 palette entries.
+func downsample(g []Attribute, level Level) []Attribute {
+	if len(g) < 3 || (g[0] != extendedFg && g[0] != extendedBg && g[0] != extendedUnderlineColour) {
+		return g
+	}
+
+	var rgb [3]uint8
+	switch {
+	case g[1] == extendedRGB && len(g) == 5:
+		if level >= LevelTrueColour {
+			return g
+		}
+		rgb = [3]uint8{uint8(g[2]), uint8(g[3]), uint8(g[4])}
+		if level >= Level256 {
+			return []Attribute{g[0], extendedIndexed, Attribute(nearest256(rgb))}
+		}
+	case g[1] == extendedIndexed:
+		if level >= Level256 {
+			return g
+		}
+		rgb = paletteRGB(uint8(g[2]))
+	default:
+		return g
+	}
+
+	n := Attribute(nearestBasic(rgb))
+	if g[0] == extendedUnderlineColour {
+		return []Attribute{extendedUnderlineColour, extendedIndexed, n}
+	}
+
+	base := FgBlack
+	if g[0] == extendedBg {
+		base = BgBlack
+	}
+	if n >= 8 {
+		// the bright colours start 60 codes higher
+		n += 60 - 8
+	}
+
+	return []Attribute{base + n}
+}
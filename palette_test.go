@@ -0,0 +1,77 @@
+package colour
+
+import "testing"
+
+func TestPaletteRGB(t *testing.T) {
+	tests := []struct {
+		n    uint8
+		want [3]uint8
+	}{
+		{1, [3]uint8{205, 0, 0}},
+		{16, [3]uint8{0, 0, 0}},
+		{196, [3]uint8{255, 0, 0}},
+		{208, [3]uint8{255, 135, 0}},
+		{231, [3]uint8{255, 255, 255}},
+		{232, [3]uint8{8, 8, 8}},
+		{255, [3]uint8{238, 238, 238}},
+	}
+
+	for _, tt := range tests {
+		if got := paletteRGB(tt.n); got != tt.want {
+			t.Errorf("%d want: %v, got: %v", tt.n, tt.want, got)
+		}
+	}
+}
+
+func TestNearest256(t *testing.T) {
+	tests := []struct {
+		rgb  [3]uint8
+		want uint8
+	}{
+		{[3]uint8{255, 0, 0}, 196},
+		{[3]uint8{250, 5, 5}, 196},
+		{[3]uint8{255, 135, 0}, 208},
+		{[3]uint8{0, 0, 0}, 16},
+		{[3]uint8{128, 128, 128}, 244},
+		{[3]uint8{255, 255, 255}, 231},
+		{[3]uint8{95, 135, 175}, 67},
+	}
+
+	for _, tt := range tests {
+		if got := nearest256(tt.rgb); got != tt.want {
+			t.Errorf("%v want: %d, got: %d", tt.rgb, tt.want, got)
+		}
+	}
+}
+
+func TestDownsample(t *testing.T) {
+	NoColour = false
+	defer SetLevel(GetLevel())
+
+	tests := []struct {
+		level Level
+		c     *Colour
+		want  string
+	}{
+		{LevelTrueColour, RGB(123, 200, 55), "\x1b[38;2;123;200;55m"},
+		{Level256, RGB(123, 200, 55), "\x1b[38;5;113m"},
+		{Level16, RGB(123, 200, 55), "\x1b[33m"},
+		{Level16, RGB(30, 200, 40), "\x1b[32m"},
+		{Level16, RGB(250, 10, 10), "\x1b[91m"},
+		{Level16, BgRGB(0, 0, 230), "\x1b[44m"},
+		{Level16, BgRGB(250, 250, 250), "\x1b[107m"},
+		{Level256, NewFg256(208), "\x1b[38;5;208m"},
+		{Level16, NewFg256(208), "\x1b[33m"},
+		{Level16, New(Bold, FgRed).AddBgRGB(0, 0, 0), "\x1b[1;31;40m"},
+		{LevelNone, RGB(255, 255, 0), "\x1b[93m"},
+		{Level256, New().UnderlineColourRGB(123, 200, 55), "\x1b[58;5;113m"},
+		{Level16, New().UnderlineColourRGB(250, 10, 10), "\x1b[58;5;9m"},
+	}
+
+	for i, tt := range tests {
+		SetLevel(tt.level)
+		if got := tt.c.format(); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package colour
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseSequence parses the SGR sequences at the start of s, such as
+// "\x1b[1;31m\x1b[48;5;21m", into one colour each. It is the inverse of
+// formatting a colour, so ANSI coloured text from elsewhere can be
+// reconstructed and reapplied. Extended colours are kept together in both
+// the "38;5;n" and the colon separated "38:5:n" forms, and the colon forms of
+// the underline styles are recognised.
+//
+// Parsing stops at the first text or escape sequence other than SGR. An error
+// is returned if a sequence is incomplete or holds parameters that are not
+// valid.
+func ParseSequence(s string) ([]*Colour, error) {
+	var colours []*Colour
+	for strings.HasPrefix(s, escape+"[") {
+		l := escapeLen(s)
+		if l < 0 {
+			return nil, fmt.Errorf("colour: incomplete escape sequence %q", s)
+		}
+
+		seq := s[:l]
+		if final := seq[l-1]; final < 0x40 || final > 0x7e {
+			return nil, fmt.Errorf("colour: malformed escape sequence %q", seq)
+		}
+		if !isSGR(seq) {
+			break
+		}
+
+		c, err := parseSGR(seq[2 : l-1])
+		if err != nil {
+			return nil, fmt.Errorf("colour: invalid sequence %q: %v", seq, err)
+		}
+		colours = append(colours, c)
+		s = s[l:]
+	}
+
+	return colours, nil
+}
+
+// parseSGR parses the parameters of an SGR sequence.
+func parseSGR(s string) (*Colour, error) {
+	c := New()
+	if s == "" {
+		return c.Add(Reset), nil
+	}
+
+	fields := strings.Split(s, ";")
+	for i := 0; i < len(fields); i++ {
+		if strings.IndexByte(fields[i], ':') >= 0 {
+			g, err := parseColonParam(fields[i])
+			if err != nil {
+				return nil, err
+			}
+			c.Add(g...)
+			continue
+		}
+
+		a, err := parseParam(fields[i])
+		if err != nil {
+			return nil, err
+		}
+
+		switch a {
+		case extendedFg, extendedBg, extendedUnderlineColour:
+			if i+1 == len(fields) {
+				return nil, fmt.Errorf("missing colour after %d", a)
+			}
+			g, err := parseExtended(a, fields[i+1:])
+			if err != nil {
+				return nil, err
+			}
+			c.Add(g...)
+			i += len(g) - 1
+		default:
+			c.Add(a)
+		}
+	}
+
+	return c, nil
+}
+
+// parseExtended parses the arguments of the extended colour a, given as the
+// fields following it. Only the fields used are consumed.
+func parseExtended(a Attribute, fields []string) ([]Attribute, error) {
+	mode, err := parseParam(fields[0])
+	if err != nil {
+		return nil, err
+	}
+
+	n := 0
+	switch mode {
+	case extendedIndexed:
+		n = 1
+	case extendedRGB:
+		n = 3
+	default:
+		return nil, fmt.Errorf("unknown colour mode %d after %d", mode, a)
+	}
+	if len(fields) < n+1 {
+		return nil, fmt.Errorf("missing colour components after %d;%d", a, mode)
+	}
+
+	g := []Attribute{a, mode}
+	for _, f := range fields[1 : n+1] {
+		v, err := parseParam(f)
+		if err != nil {
+			return nil, err
+		}
+		g = append(g, v)
+	}
+
+	return g, nil
+}
+
+// parseColonParam parses a parameter with colon separated sub-parameters,
+// such as "4:3" or "38:2::255:0:0", into the equivalent parameter group.
+func parseColonParam(s string) ([]Attribute, error) {
+	parts := strings.Split(s, ":")
+	a, err := parseParam(parts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	switch a {
+	case Underline:
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid underline style %q", s)
+		}
+		style, err := parseParam(parts[1])
+		if err != nil {
+			return nil, err
+		}
+		switch style {
+		case 0:
+			return []Attribute{24}, nil
+		case 1:
+			return []Attribute{Underline}, nil
+		case 2:
+			return []Attribute{DoubleUnderline}, nil
+		case 3, 4, 5:
+			return []Attribute{colonParam | Underline<<8 | style}, nil
+		}
+		return nil, fmt.Errorf("unknown underline style %q", s)
+
+	case extendedFg, extendedBg, extendedUnderlineColour:
+		args := parts[1:]
+		if len(args) == 5 && args[0] == "2" {
+			// drop the colour space identifier of "38:2:id:r:g:b"
+			args = append(args[:1], args[2:]...)
+		}
+		if len(args) == 0 {
+			return nil, fmt.Errorf("missing colour in %q", s)
+		}
+		g, err := parseExtended(a, args)
+		if err != nil {
+			return nil, err
+		}
+		if len(g)-1 != len(args) {
+			return nil, fmt.Errorf("too many colour components in %q", s)
+		}
+		return g, nil
+	}
+
+	return nil, fmt.Errorf("unexpected sub-parameters in %q", s)
+}
+
+// parseParam parses a single numeric parameter. An empty parameter is 0.
+func parseParam(s string) (Attribute, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 || strings.IndexAny(s, "+-") >= 0 {
+		return 0, fmt.Errorf("invalid parameter %q", s)
+	}
+	if n > 255 {
+		return 0, fmt.Errorf("parameter %q out of range", s)
+	}
+
+	return Attribute(n), nil
+}
@@ -0,0 +1,90 @@
+package colour
+
+import "testing"
+
+func TestParseSequence(t *testing.T) {
+	tests := []struct {
+		s    string
+		want []*Colour
+	}{
+		{"", nil},
+		{"plain", nil},
+		{"\x1b[31m", []*Colour{New(FgRed)}},
+		{"\x1b[1;31mtext\x1b[0m", []*Colour{New(Bold, FgRed)}},
+		{"\x1b[m", []*Colour{New(Reset)}},
+		{"\x1b[1;;4m", []*Colour{New(Bold, Reset, Underline)}},
+		{"\x1b[1m\x1b[38;5;196m", []*Colour{New(Bold), NewFg256(196)}},
+		{"\x1b[38;2;1;2;3;48;5;4;1m", []*Colour{RGB(1, 2, 3).AddBg256(4).Add(Bold)}},
+		{"\x1b[38:5:196m", []*Colour{NewFg256(196)}},
+		{"\x1b[48:2::1:2:3m", []*Colour{BgRGB(1, 2, 3)}},
+		{"\x1b[4:3;58:2:1:2:3m", []*Colour{New(CurlyUnderline).UnderlineColourRGB(1, 2, 3)}},
+		{"\x1b[4:2;4:0m", []*Colour{New(DoubleUnderline, 24)}},
+		// stops at anything else
+		{"\x1b[31m\x1b[K\x1b[32m", []*Colour{New(FgRed)}},
+	}
+
+	for i, tt := range tests {
+		got, err := ParseSequence(tt.s)
+		if err != nil {
+			t.Errorf("[%d] unexpected error: %v", i, err)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("[%d] want: %d colours, got: %d", i, len(tt.want), len(got))
+			continue
+		}
+		for j := range got {
+			if !got[j].Equals(tt.want[j]) || got[j].sequence() != tt.want[j].sequence() {
+				t.Errorf("[%d] want: %q, got: %q", i, tt.want[j].sequence(), got[j].sequence())
+			}
+		}
+	}
+}
+
+func TestParseSequenceErrors(t *testing.T) {
+	tests := []string{
+		"\x1b[31",
+		"\x1b[3\x01m",
+		"\x1b[3?m",
+		"\x1b[-1m",
+		"\x1b[256m",
+		"\x1b[38m",
+		"\x1b[38;5m",
+		"\x1b[38;2;1;2m",
+		"\x1b[38;7;1m",
+		"\x1b[38;2;1;2;300m",
+		"\x1b[4:9m",
+		"\x1b[1:2m",
+		"\x1b[38:5:1:2m",
+		"\x1b[31m\x1b[1",
+	}
+
+	for i, s := range tests {
+		if got, err := ParseSequence(s); err == nil {
+			t.Errorf("[%d] %q: want error, got: %v", i, s, got)
+		}
+	}
+}
+
+func TestParseSequenceRoundTrip(t *testing.T) {
+	defer SetLevel(GetLevel())
+	SetLevel(LevelTrueColour)
+	defer SetExtendedUnderline(GetExtendedUnderline())
+	SetExtendedUnderline(true)
+
+	colours := []*Colour{
+		New(FgRed, Bold, BgHiWhite),
+		New(Italic).AddRGB(10, 20, 30).AddBg256(200),
+		New(DashedUnderline, Overline).UnderlineColour256(9),
+	}
+
+	for i, c := range colours {
+		got, err := ParseSequence(c.format())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 || !got[0].Equals(c) {
+			t.Errorf("[%d] %q did not round trip", i, c.format())
+		}
+	}
+}
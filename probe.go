@@ -0,0 +1,122 @@
+package colour
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// probeQuery sets a truecolour background and asks for the SGR attributes in
+// effect with DECRQSS, does the same for a 256 colour background, resets the
+// attributes and asks for the cursor position. Terminals answer in order and
+// all of them report the cursor position, so that reply ends the probe.
+const probeQuery = escape + "[0;48;2;1;2;3m" + escape + "P$qm" + escape + "\\" +
+	escape + "[0;48;5;123m" + escape + "P$qm" + escape + "\\" +
+	escape + "[0m" + escape + "[6n"
+
+var (
+	// probeSGR matches a DECRQSS reply reporting the SGR attributes.
+	probeSGR = regexp.MustCompile(`\x1bP[01]\$r([0-9;:]*)m`)
+
+	// probeCursor matches a cursor position report.
+	probeCursor = regexp.MustCompile(`\x1b\[[0-9]+;[0-9]+R`)
+)
+
+// ProbeTerminal asks the terminal connected to rw which colours it displays,
+// which is more reliable than the environment DetectLevel looks at, for
+// example over SSH where COLORTERM is usually not passed on. It returns the
+// number of colours like TerminalColours: 16777216 if the terminal keeps a
+// truecolour background as given, 256 if it keeps a 256 colour one, and 16
+// if it answers but keeps neither, as do terminals not supporting the
+// DECRQSS query used. The result can be passed on with SetDetector.
+//
+// Probing is never done automatically. rw is usually the controlling
+// terminal, which must be in raw mode so the replies can be read as they
+// arrive and are not echoed. The probe changes the attributes of the
+// terminal transiently and ends with a reset, so any colour set before is
+// turned off. An error is returned if no reply arrives within timeout. The
+// read is then abandoned, but unless rw supports read deadlines, like
+// *os.File for some files and net.Conn, it stays blocked in the background
+// and discards the next input that arrives.
+func ProbeTerminal(rw io.ReadWriter, timeout time.Duration) (level int, err error) {
+	if _, err := io.WriteString(rw, probeQuery); err != nil {
+		return 0, err
+	}
+
+	reply, err := readProbeReply(rw, timeout)
+	if err != nil {
+		return 0, err
+	}
+
+	return probeLevel(reply), nil
+}
+
+// readProbeReply reads from r until a cursor position report arrives, for at
+// most timeout.
+func readProbeReply(r io.Reader, timeout time.Duration) ([]byte, error) {
+	if d, ok := r.(interface{ SetReadDeadline(time.Time) error }); ok {
+		if d.SetReadDeadline(time.Now().Add(timeout)) == nil {
+			defer d.SetReadDeadline(time.Time{})
+		}
+	}
+
+	chunks := make(chan []byte)
+	errs := make(chan error, 1)
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		for {
+			buf := make([]byte, 64)
+			n, err := r.Read(buf)
+			if n > 0 {
+				select {
+				case chunks <- buf[:n]:
+				case <-done:
+					return
+				}
+			}
+			if err != nil {
+				errs <- err
+				return
+			}
+		}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var reply []byte
+	for !probeCursor.Match(reply) {
+		select {
+		case b := <-chunks:
+			reply = append(reply, b...)
+		case err := <-errs:
+			return nil, fmt.Errorf("colour: reading terminal reply: %v", err)
+		case <-timer.C:
+			return nil, fmt.Errorf("colour: no reply from terminal within %v", timeout)
+		}
+	}
+
+	return reply, nil
+}
+
+// probeLevel returns the number of colours of a terminal from its reply to
+// probeQuery. Terminals report the attributes separated by ';' or ':', the
+// latter with an empty colour space for truecolour.
+func probeLevel(reply []byte) int {
+	level := Level16
+	for i, m := range probeSGR.FindAllSubmatch(reply, 2) {
+		p := ";" + strings.Replace(string(m[1]), ":", ";", -1) + ";"
+		switch {
+		case i == 0 && (strings.Contains(p, ";48;2;1;2;3;") || strings.Contains(p, ";48;2;;1;2;3;")):
+			return int(LevelTrueColour)
+		case i == 1 && strings.Contains(p, ";48;5;123;"):
+			level = Level256
+		}
+	}
+
+	return int(level)
+}
@@ -0,0 +1,74 @@
+package colour
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// probeTerminal answers the probe with a fixed reply.
+type probeTerminal struct {
+	io.Reader
+	bytes.Buffer
+}
+
+func (t *probeTerminal) Write(p []byte) (int, error) {
+	return t.Buffer.Write(p)
+}
+
+func (t *probeTerminal) Read(p []byte) (int, error) {
+	return t.Reader.Read(p)
+}
+
+func TestProbeTerminal(t *testing.T) {
+	const cursor = "\x1b[12;1R"
+
+	tests := []struct {
+		reply string
+		want  int
+	}{
+		{"\x1bP1$r0;48:2::1:2:3m\x1b\\\x1bP1$r0;48:5:123m\x1b\\" + cursor, 1 << 24},
+		{"\x1bP1$r0;48;2;1;2;3m\x1b\\\x1bP1$r0;48;5;123m\x1b\\" + cursor, 1 << 24},
+		{"\x1bP1$r0;48:5:16m\x1b\\\x1bP1$r0;48:5:123m\x1b\\" + cursor, 256},
+		{"\x1bP1$r0;41m\x1b\\\x1bP1$r0;44m\x1b\\" + cursor, 16},
+		{cursor, 16},
+	}
+
+	for i, tt := range tests {
+		rw := &probeTerminal{Reader: strings.NewReader(tt.reply)}
+		got, err := ProbeTerminal(rw, time.Second)
+		if err != nil {
+			t.Errorf("[%d] unexpected error: %v", i, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("[%d] want: %d, got: %d", i, tt.want, got)
+		}
+		if rw.String() != probeQuery {
+			t.Errorf("[%d] want: %q, got: %q", i, probeQuery, rw.String())
+		}
+	}
+}
+
+func TestProbeTerminalTimeout(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+
+	start := time.Now()
+	_, err := ProbeTerminal(&probeTerminal{Reader: r}, 20*time.Millisecond)
+	if err == nil {
+		t.Fatal("want error, got nil")
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("probe took %v", d)
+	}
+}
+
+func TestProbeTerminalEOF(t *testing.T) {
+	_, err := ProbeTerminal(&probeTerminal{Reader: strings.NewReader("")}, time.Second)
+	if err == nil {
+		t.Error("want error, got nil")
+	}
+}
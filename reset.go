@@ -0,0 +1,116 @@
+package colour
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NestedReset makes coloured strings end by cancelling only the attributes
+// they set, such as 39 for the foreground or 22 for bold, instead of resetting
+// everything. Attributes of a different kind set by an enclosing block are
+// preserved, for example text printed in bold between Set(FgBlue) and Unset()
+// leaves the text after it blue. Attributes of the same kind are not
+// restored: cancelling a foreground returns to the default one, so a
+// RedString inside the same block leaves the text after it in the default
+// colour, not blue. Use a Stack to restore those. Colours holding attributes
+// without a specific cancel code still end with a full reset.
+var NestedReset = false
+
+// ResetBefore makes coloured output start with a full reset before the
+// sequence of its colour, so that attributes left behind by an earlier
+// program, such as bold, do not bleed into it. It applies wherever a colour
+// is turned on, by Set and the print functions alike. As the reset clears
+// everything, it does not combine with NestedReset for colours printed inside
+// others.
+var ResetBefore = false
+
+// startSequence returns the sequence turning c on, preceded by a reset if
+// ResetBefore is set.
+func (c *Colour) startSequence() string {
+	if ResetBefore {
+		return resetSequence + c.format()
+	}
+
+	return c.format()
+}
+
+// cancelCode returns the SGR parameter turning off the parameter group g, or
+// false if there is none.
+func cancelCode(g []Attribute) (Attribute, bool) {
+	switch a := g[0]; {
+	case a == Bold || a == Faint:
+		return 22, true
+	case a == Italic:
+		return 23, true
+	case a == Underline || isExtendedUnderline(a):
+		return 24, true
+	case a == BlinkSlow || a == BlinkRapid:
+		return 25, true
+	case a == ReverseVideo:
+		return 27, true
+	case a == Concealed:
+		return 28, true
+	case a == CrossedOut:
+		return 29, true
+	case a == Overline:
+		return 55, true
+	case a == Framed || a == Encircled:
+		return 54, true
+	case a > PrimaryFont && a <= Font9:
+		return PrimaryFont, true
+	case a == extendedUnderlineColour:
+		return DefaultUnderlineColour, true
+	case isBackground(g):
+		return 49, true
+	case isForeground(g):
+		return 39, true
+	}
+
+	return 0, false
+}
+
+// cancelSequence returns the SGR parameters turning off every attribute of
+// c, or "0" if some cannot be turned off individually.
+func (c *Colour) cancelSequence() string {
+	var codes []string
+	seen := make(map[Attribute]bool)
+	for _, g := range c.groups() {
+		code, ok := cancelCode(substitute(g))
+		if !ok {
+			return "0"
+		}
+		if !seen[code] {
+			seen[code] = true
+			codes = append(codes, strconv.Itoa(int(code)))
+		}
+	}
+
+	if len(codes) == 0 {
+		return "0"
+	}
+
+	return strings.Join(codes, ";")
+}
+
+// SetPartial is like Set but returns a function that cancels only the
+// attributes of c, such as 39 for the foreground, 49 for the background or 22
+// for bold, instead of resetting everything. Attributes set before it are
+// left intact. If Set wrote nothing, restore does nothing either:
+//
+//	restore := colour.New(colour.Bold).SetPartial()
+//	defer restore()
+func (c *Colour) SetPartial() (restore func()) {
+	prefix, _ := c.affixes()
+	if prefix != "" {
+		fmt.Fprint(GetOutput(), prefix)
+	}
+
+	return func() {
+		if prefix == "" {
+			return
+		}
+
+		fmt.Fprintf(GetOutput(), "%s[%sm", escape, c.cancelSequence())
+	}
+}
@@ -0,0 +1,111 @@
+package colour
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestNestedReset(t *testing.T) {
+	NoColour = false
+	defer SetLevel(GetLevel())
+	SetLevel(LevelTrueColour)
+	defer SetExtendedUnderline(GetExtendedUnderline())
+	SetExtendedUnderline(true)
+
+	NestedReset = true
+	defer func() { NestedReset = false }()
+
+	tests := []struct {
+		c    *Colour
+		want string
+	}{
+		{New(FgRed), "\x1b[31mx\x1b[39m"},
+		{New(FgHiRed, BgBlue), "\x1b[91;44mx\x1b[39;49m"},
+		{New(Bold, Faint, Underline), "\x1b[1;2;4mx\x1b[22;24m"},
+		{New(Italic, BlinkSlow, ReverseVideo, Concealed, CrossedOut), "\x1b[3;5;7;8;9mx\x1b[23;25;27;28;29m"},
+		{New(Overline, Bold), "\x1b[53;1mx\x1b[55;22m"},
+		{New(Framed, Encircled, Font3), "\x1b[51;52;13mx\x1b[54;10m"},
+		{New(CurlyUnderline, DoubleUnderline), "\x1b[4:3;21mx\x1b[24m"},
+		{New(Underline).UnderlineColour256(1), "\x1b[4;58;5;1mx\x1b[24;59m"},
+		{NewFg256(1).AddBgRGB(1, 2, 3), "\x1b[38;5;1;48;2;1;2;3mx\x1b[39;49m"},
+		{New(FgRed, Reset), "\x1b[31;0mx\x1b[0m"},
+		{New(FgRed, 200), "\x1b[31;200mx\x1b[0m"},
+	}
+
+	for i, tt := range tests {
+		if got := tt.c.Sprint("x"); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+	}
+
+	// an enclosing colour survives
+	rb := new(bytes.Buffer)
+	Output = rb
+	Set(FgBlue)
+	New(Bold).Print("bold")
+	Unset()
+
+	if got, want := rb.String(), "\x1b[34m\x1b[1mbold\x1b[22m\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+
+	// one of the same kind is cancelled, not restored
+	rb.Reset()
+	Set(FgBlue)
+	New(FgRed).Print("r")
+	fmt.Fprint(rb, "after")
+	Unset()
+
+	if got, want := rb.String(), "\x1b[34m\x1b[31mr\x1b[39mafter\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+}
+
+func TestSetPartial(t *testing.T) {
+	NoColour = false
+	oldOut := GetOutput()
+	defer SetOutput(oldOut)
+
+	rb := new(bytes.Buffer)
+	SetOutput(rb)
+
+	Set(FgBlue)
+	restore := New(Bold, BgWhite).SetPartial()
+	fmt.Fprint(rb, "x")
+	restore()
+	Unset()
+
+	if got, want := rb.String(), "\x1b[34m\x1b[1;47mx\x1b[22;49m\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+
+	rb.Reset()
+	c := New(FgRed)
+	c.DisableColour()
+	c.SetPartial()()
+	if got := rb.String(); got != "" {
+		t.Errorf("want: %q, got: %q", "", got)
+	}
+}
+
+func TestResetBefore(t *testing.T) {
+	NoColour = false
+	ResetBefore = true
+	defer func() { ResetBefore = false }()
+
+	c := New(FgRed, Bold)
+	if got, want := c.Sprint("x"), "\x1b[0m\x1b[31;1mx\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+
+	var buf bytes.Buffer
+	c.Fprint(&buf, "x")
+	if got, want := buf.String(), "\x1b[0m\x1b[31;1mx\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+
+	if got := New().Sprint("x"); got != "x" {
+		t.Errorf("want: %q, got: %q", "x", got)
+	}
+}
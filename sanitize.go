@@ -0,0 +1,77 @@
+package colour
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+)
+
+// sanitizeArgs is non-zero when printed arguments are sanitized.
+var sanitizeArgs int32
+
+// SetGlobalSanitize enables or disables sanitizing of printed arguments. When
+// enabled, escape sequences and C1 control characters are removed from the
+// formatted arguments of every print function and helper before the colour
+// is applied, so untrusted data cannot inject sequences of its own. The
+// sequences written by the package itself are unaffected.
+func SetGlobalSanitize(enable bool) {
+	var v int32
+	if enable {
+		v = 1
+	}
+	atomic.StoreInt32(&sanitizeArgs, v)
+}
+
+// sanitize returns s with escape sequences and C1 control characters removed
+// if sanitizing is enabled.
+func sanitize(s string) string {
+	if atomic.LoadInt32(&sanitizeArgs) == 0 {
+		return s
+	}
+
+	s = stripEscapes(s)
+	if strings.IndexFunc(s, isC1) < 0 {
+		return s
+	}
+
+	return strings.Map(func(r rune) rune {
+		if isC1(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// isC1 reports whether r is a C1 control character, which terminals may
+// treat like the escape sequence it abbreviates (U+009B is CSI).
+func isC1(r rune) bool {
+	return r >= 0x80 && r <= 0x9f
+}
+
+// fprint is fmt.Fprint, sanitizing the formatted arguments if enabled.
+func fprint(w io.Writer, a ...interface{}) (int, error) {
+	if atomic.LoadInt32(&sanitizeArgs) == 0 {
+		return fmt.Fprint(w, a...)
+	}
+
+	return io.WriteString(w, sanitize(fmt.Sprint(a...)))
+}
+
+// fprintf is fmt.Fprintf, sanitizing the formatted arguments if enabled.
+func fprintf(w io.Writer, format string, a ...interface{}) (int, error) {
+	if atomic.LoadInt32(&sanitizeArgs) == 0 {
+		return fmt.Fprintf(w, format, a...)
+	}
+
+	return io.WriteString(w, sanitize(fmt.Sprintf(format, a...)))
+}
+
+// fprintln is fmt.Fprintln, sanitizing the formatted arguments if enabled.
+func fprintln(w io.Writer, a ...interface{}) (int, error) {
+	if atomic.LoadInt32(&sanitizeArgs) == 0 {
+		return fmt.Fprintln(w, a...)
+	}
+
+	return io.WriteString(w, sanitize(fmt.Sprintln(a...)))
+}
@@ -0,0 +1,102 @@
+package colour
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	NoColour = false
+	rb := new(bytes.Buffer)
+	Output = rb
+
+	evil := "a\x1b[2Jb\x1b]0;title\x07c\u009b31md"
+	red := New(FgRed)
+
+	// off by default
+	if got, want := red.Sprint(evil), "\x1b[31m"+evil+"\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+
+	SetGlobalSanitize(true)
+	defer SetGlobalSanitize(false)
+
+	tests := []struct {
+		name string
+		f    func() string
+		want string
+	}{
+		{"Sprint", func() string { return red.Sprint(evil) }, "\x1b[31mabc31md\x1b[0m"},
+		{"Sprintf", func() string { return red.Sprintf("%s!", evil) }, "\x1b[31mabc31md!\x1b[0m"},
+		{"Sprintln", func() string { return red.Sprintln(evil) }, "\x1b[31mabc31md\n\x1b[0m"},
+		{"SprintFunc", func() string { return red.SprintFunc()(evil) }, "\x1b[31mabc31md\x1b[0m"},
+		{"RedString", func() string { return RedString("%s", evil) }, "\x1b[31mabc31md\x1b[0m"},
+		{"Print", func() string { red.Print(evil); return rb.String() }, "\x1b[31mabc31md\x1b[0m"},
+		{"Printf", func() string { red.Printf("%s", evil); return rb.String() }, "\x1b[31mabc31md\x1b[0m"},
+		{"Println", func() string { red.Println(evil); return rb.String() }, "\x1b[31mabc31md\n\x1b[0m"},
+		{"Fprint", func() string { red.Fprint(rb, evil); return rb.String() }, "\x1b[31mabc31md\x1b[0m"},
+	}
+
+	for _, tt := range tests {
+		rb.Reset()
+		if got := tt.f(); got != tt.want {
+			t.Errorf("%s want: %q, got: %q", tt.name, tt.want, got)
+		}
+	}
+
+	// disabled colours are sanitized too
+	red.DisableColour()
+	if got, want := red.Sprint(evil), "abc31md"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+}
+
+func TestSanitizePlain(t *testing.T) {
+	NoColour = false
+	SetGlobalSanitize(true)
+	defer SetGlobalSanitize(false)
+
+	evil := "a\x1b[2Jb\x1b]0;title\x07c\u009b31md"
+
+	table := func() string {
+		var buf bytes.Buffer
+		NewTable([]string{"h"}).SetHeaderStyle(nil).AddRow(evil).Render(&buf)
+		return buf.String()
+	}
+	line := func() string {
+		var buf bytes.Buffer
+		NewLine(&buf).Update(nil, evil)
+		return buf.String()
+	}
+	highlightWriter := func() string {
+		var buf bytes.Buffer
+		hw := NewHighlightWriter(&buf, []Rule{{regexp.MustCompile(`b`), New(FgRed)}})
+		hw.Write([]byte(evil))
+		hw.Flush()
+		return buf.String()
+	}
+
+	tests := []struct {
+		name string
+		f    func() string
+		want string
+	}{
+		{"ImageFallback", func() string { return ImageFallback(evil, nil) }, "[image: abc31md]"},
+		{"Value", func() string { return fmt.Sprint(Value{nil, evil}) }, "abc31md"},
+		{"KV", func() string { return KV(evil, 1, nil, nil) }, "abc31md=1"},
+		{"Table", table, "h\n-------\nabc31md\n"},
+		{"Line", line, "\rabc31md"},
+		{"Highlight", func() string { return New(FgRed).Highlight(evil, "b") }, "a\x1b[31mb\x1b[0mc31md"},
+		{"HighlightEmpty", func() string { return New(FgRed).Highlight(evil, "") }, "abc31md"},
+		{"HighlightRegexp", func() string { return New(FgRed).HighlightRegexp(evil, regexp.MustCompile(`b`)) }, "a\x1b[31mb\x1b[0mc31md"},
+		{"HighlightWriter", highlightWriter, "a\x1b[31mb\x1b[0mc31md"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.f(); got != tt.want {
+			t.Errorf("%s want: %q, got: %q", tt.name, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+//go:build go1.21
+// +build go1.21
+
+package colour
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SlogOptions configures a handler returned by NewSlogHandler. The zero value
+// gives the default colours and logs records of level Info and above.
+type SlogOptions struct {
+	// Level is the minimum level logged, slog.LevelInfo if nil.
+	Level slog.Leveler
+
+	// LevelColours maps levels to the colour their name is shown in. A
+	// record uses the colour of the highest level not above its own. The
+	// defaults of DefaultSlogLevelColours are used if nil.
+	LevelColours map[slog.Level]*Colour
+
+	// TimeColour, KeyColour and ValueColour colour the timestamp and the
+	// keys and values of attributes. They are left plain if nil.
+	TimeColour  *Colour
+	KeyColour   *Colour
+	ValueColour *Colour
+
+	// TimeFormat is the layout of timestamps, "15:04:05.000" if empty.
+	TimeFormat string
+
+	// ForceColour colours the output even if w is not a terminal. The global
+	// NoColour setting still applies.
+	ForceColour bool
+}
+
+// DefaultSlogLevelColours are the level colours used by NewSlogHandler unless
+// set in SlogOptions.
+var DefaultSlogLevelColours = map[slog.Level]*Colour{
+	slog.LevelDebug: New(FgHiBlack),
+	slog.LevelInfo:  New(FgCyan),
+	slog.LevelWarn:  New(FgYellow),
+	slog.LevelError: New(FgRed, Bold),
+}
+
+// SlogHandler is a slog.Handler writing one line per record with the level
+// coloured, followed by the message and the attributes as key=value pairs:
+//
+//	15:04:05.000 INFO  listening addr=:8080
+type SlogHandler struct {
+	opts   SlogOptions
+	levels []slog.Level // keys of opts.LevelColours, in ascending order
+	colour bool
+
+	mu    *sync.Mutex
+	w     io.Writer
+	attrs string // preformatted attributes from WithAttrs
+	group string // key prefix from WithGroup, ending in "."
+}
+
+// NewSlogHandler returns a handler writing to w. Output is coloured if w is a
+// terminal according to SupportsColour, or if forced with opts.ForceColour,
+// and colour is not disabled globally. opts may be nil for the defaults.
+func NewSlogHandler(w io.Writer, opts *SlogOptions) *SlogHandler {
+	h := &SlogHandler{mu: new(sync.Mutex), w: w}
+	if opts != nil {
+		h.opts = *opts
+	}
+	if h.opts.LevelColours == nil {
+		h.opts.LevelColours = DefaultSlogLevelColours
+	}
+	if h.opts.TimeFormat == "" {
+		h.opts.TimeFormat = "15:04:05.000"
+	}
+
+	for l := range h.opts.LevelColours {
+		h.levels = append(h.levels, l)
+	}
+	sort.Slice(h.levels, func(i, j int) bool { return h.levels[i] < h.levels[j] })
+
+	h.colour = h.opts.ForceColour || SupportsColour(w)
+
+	return h
+}
+
+// Enabled reports whether records of level l are logged.
+func (h *SlogHandler) Enabled(_ context.Context, l slog.Level) bool {
+	min := slog.LevelInfo
+	if h.opts.Level != nil {
+		min = h.opts.Level.Level()
+	}
+
+	return l >= min
+}
+
+// Handle writes r as a single line.
+func (h *SlogHandler) Handle(_ context.Context, r slog.Record) error {
+	var b strings.Builder
+	if !r.Time.IsZero() {
+		b.WriteString(h.paint(h.opts.TimeColour, r.Time.Format(h.opts.TimeFormat)))
+		b.WriteByte(' ')
+	}
+	b.WriteString(padRight(h.paint(h.levelColour(r.Level), r.Level.String()), 5))
+	b.WriteByte(' ')
+	b.WriteString(sanitize(r.Message))
+	b.WriteString(h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		h.appendAttr(&b, h.group, a)
+		return true
+	})
+	b.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
+
+// WithAttrs returns a handler adding attrs to every record.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var b strings.Builder
+	for _, a := range attrs {
+		h.appendAttr(&b, h.group, a)
+	}
+
+	h2 := *h
+	h2.attrs += b.String()
+	return &h2
+}
+
+// WithGroup returns a handler qualifying the keys of later attributes with
+// name, separated by a dot.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	h2 := *h
+	h2.group += name + "."
+	return &h2
+}
+
+// appendAttr writes a to b preceded by a space, flattening groups.
+func (h *SlogHandler) appendAttr(b *strings.Builder, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			h.appendAttr(b, prefix, ga)
+		}
+		return
+	}
+
+	var v interface{} = a.Value.String()
+	if a.Value.Kind() == slog.KindTime {
+		v = a.Value.Time().Format(time.RFC3339Nano)
+	}
+
+	keyColour, valColour := h.opts.KeyColour, h.opts.ValueColour
+	if !h.colour {
+		keyColour, valColour = nil, nil
+	}
+
+	b.WriteByte(' ')
+	b.WriteString(KV(prefix+a.Key, v, keyColour, valColour))
+}
+
+// levelColour returns the colour for records of level l.
+func (h *SlogHandler) levelColour(l slog.Level) *Colour {
+	var c *Colour
+	for _, lc := range h.levels {
+		if lc > l {
+			break
+		}
+		c = h.opts.LevelColours[lc]
+	}
+
+	return c
+}
+
+// paint colours s with c if the handler colours its output.
+func (h *SlogHandler) paint(c *Colour, s string) string {
+	if !h.colour {
+		return s
+	}
+
+	return styled(c, s)
+}
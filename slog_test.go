@@ -0,0 +1,67 @@
+//go:build go1.21
+// +build go1.21
+
+package colour
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestSlogHandler(t *testing.T) {
+	NoColour = false
+
+	var buf bytes.Buffer
+	h := NewSlogHandler(&buf, &SlogOptions{
+		Level:       slog.LevelDebug,
+		ForceColour: true,
+		KeyColour:   New(FgBlue),
+		TimeColour:  New(Faint),
+	})
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 6000000, time.UTC)
+	tests := []struct {
+		level slog.Level
+		msg   string
+		attrs []slog.Attr
+		want  string
+	}{
+		{slog.LevelInfo, "hello", nil, "\x1b[2m03:04:05.006\x1b[0m \x1b[36mINFO\x1b[0m  hello\n"},
+		{slog.LevelError, "failed", []slog.Attr{slog.String("err", "no such file")}, "\x1b[2m03:04:05.006\x1b[0m \x1b[31;1mERROR\x1b[0m failed \x1b[34merr\x1b[0m=\"no such file\"\n"},
+		{slog.LevelWarn + 1, "w", nil, "\x1b[2m03:04:05.006\x1b[0m \x1b[33mWARN+1\x1b[0m w\n"},
+		{slog.LevelDebug - 1, "d", nil, "\x1b[2m03:04:05.006\x1b[0m DEBUG-1 d\n"},
+	}
+
+	for i, tt := range tests {
+		buf.Reset()
+		r := slog.NewRecord(now, tt.level, tt.msg, 0)
+		r.AddAttrs(tt.attrs...)
+		if err := h.Handle(context.Background(), r); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+	}
+}
+
+func TestSlogHandlerPlain(t *testing.T) {
+	defer setenv("FORCE_COLOR", nil)()
+
+	var buf bytes.Buffer
+	logger := slog.New(NewSlogHandler(&buf, nil))
+
+	logger.Debug("hidden")
+	logger.With("a", 1).WithGroup("req").Info("msg", "id", 7, slog.Group("user", "name", "x y"))
+
+	got := buf.String()
+	if i := bytes.IndexByte(buf.Bytes(), ' '); i >= 0 {
+		got = got[i+1:]
+	}
+	if want := "INFO  msg a=1 req.id=7 req.user.name=\"x y\"\n"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+}
@@ -0,0 +1,177 @@
+package colour
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// specValues maps the names used in specs to the attributes they stand for.
+// They are derived from the names of the constants, in lower case with words
+// separated by dashes and without the "Fg" prefix, so FgHiRed is "hi-red",
+// BgWhite "bg-white" and CrossedOut "crossed-out".
+var specValues = func() map[string]Attribute {
+	m := make(map[string]Attribute, len(attributeNames))
+	for a, name := range attributeNames {
+		m[specName(name)] = a
+	}
+	return m
+}()
+
+// specNames maps attributes with a constant to their names in specs.
+var specNames = func() map[Attribute]string {
+	m := make(map[Attribute]string, len(attributeNames))
+	for a, name := range attributeNames {
+		m[a] = specName(name)
+	}
+	return m
+}()
+
+// specName returns the spec name of the attribute constant called name.
+func specName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+
+	return strings.TrimPrefix(b.String(), "fg-")
+}
+
+// ParseSpec parses a colour from a list of attributes in a form users can
+// type, for example in an environment variable such as
+// MYAPP_ERROR_COLOR="red,bold". The attributes are separated by commas,
+// spaces or both, and each is one of:
+//
+//	red, hi-red, bg-red, bg-hi-red  a basic foreground or background colour
+//	bold, underline, crossed-out    any other attribute by name
+//	256:n, bg-256:n, ul-256:n       a 256 colour palette entry n for the
+//	                                foreground, background or underline
+//	rgb:r,g,b, bg-rgb:r,g,b,        a truecolour foreground, background or
+//	ul-rgb:r,g,b                    underline colour
+//	n                               the SGR parameter n, from 0 to 255
+//
+// Names are those of the attribute constants in lower case, with words
+// separated by dashes and the "Fg" prefix dropped, and are matched ignoring
+// case. An empty spec gives a colour without attributes. An error is
+// returned for names that are not known and values out of range.
+func ParseSpec(spec string) (*Colour, error) {
+	fields := strings.FieldsFunc(strings.ToLower(spec), func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+
+	c := New()
+	for i := 0; i < len(fields); i++ {
+		tok := fields[i]
+
+		kind, value := extendedFg, tok
+		switch {
+		case strings.HasPrefix(tok, "bg-"):
+			kind, value = extendedBg, tok[len("bg-"):]
+		case strings.HasPrefix(tok, "ul-"):
+			kind, value = extendedUnderlineColour, tok[len("ul-"):]
+		}
+
+		switch {
+		case strings.HasPrefix(value, "256:"):
+			n, err := parseParam(value[len("256:"):])
+			if err != nil || value == "256:" {
+				return nil, fmt.Errorf("colour: invalid palette entry in %q", tok)
+			}
+			c.addParam(indexedParam{kind, n})
+
+		case strings.HasPrefix(value, "rgb:"):
+			if i+2 >= len(fields) {
+				return nil, fmt.Errorf("colour: missing components in %q", tok)
+			}
+			var rgb [3]Attribute
+			for j, s := range []string{value[len("rgb:"):], fields[i+1], fields[i+2]} {
+				v, err := parseParam(s)
+				if err != nil || s == "" {
+					return nil, fmt.Errorf("colour: invalid component %q in %q", s, tok)
+				}
+				rgb[j] = v
+			}
+			c.addParam(trueColourParam{kind, rgb[0], rgb[1], rgb[2]})
+			i += 2
+
+		default:
+			a, err := parseSpecAttribute(tok)
+			if err != nil {
+				return nil, err
+			}
+			c.Add(a)
+		}
+	}
+
+	return c, nil
+}
+
+// parseSpecAttribute parses an attribute given by name or SGR parameter.
+// Parameters introducing extended colours are rejected, as their colour
+// cannot follow.
+func parseSpecAttribute(tok string) (Attribute, error) {
+	if a, ok := specValues[tok]; ok {
+		return a, nil
+	}
+
+	a, err := parseParam(tok)
+	if err != nil {
+		return 0, fmt.Errorf("colour: unknown attribute %q", tok)
+	}
+
+	switch a {
+	case extendedFg, extendedBg, extendedUnderlineColour:
+		return 0, fmt.Errorf("colour: extended colour %q needs the 256: or rgb: form", tok)
+	}
+
+	return a, nil
+}
+
+// Spec returns the attributes of the colour in the form read by ParseSpec,
+// separated by commas in the order they were added, such as
+// "red,bold,underline" or "bg-256:21,rgb:255,0,0", so it can be shown to
+// users and stored in configuration. A colour without attributes gives an
+// empty string.
+//
+// Parsing the result gives an equal colour for every colour ParseSpec can
+// express. Others are written as their SGR parameters, which ParseSpec
+// rejects: an extended colour cut short, such as New(Bold, 38), gives
+// "bold,38", and a parameter without a name above 255 is written as it is.
+func (c *Colour) Spec() string {
+	params := c.paramList()
+	tokens := make([]string, 0, len(params))
+	for _, p := range params {
+		switch p := p.(type) {
+		case indexedParam:
+			tokens = append(tokens, specPrefix(p.kind)+"256:"+attrParam(p.n))
+		case trueColourParam:
+			tokens = append(tokens, specPrefix(p.kind)+"rgb:"+attrParam(p.r)+","+attrParam(p.g)+","+attrParam(p.b))
+		case basicParam:
+			if name, ok := specNames[Attribute(p)]; ok {
+				tokens = append(tokens, name)
+			} else {
+				tokens = append(tokens, attrParam(Attribute(p)))
+			}
+		}
+	}
+
+	return strings.Join(tokens, ",")
+}
+
+// specPrefix returns the prefix of extended colours of kind in specs.
+func specPrefix(kind Attribute) string {
+	switch kind {
+	case extendedBg:
+		return "bg-"
+	case extendedUnderlineColour:
+		return "ul-"
+	}
+
+	return ""
+}
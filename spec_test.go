@@ -0,0 +1,107 @@
+package colour
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSpec(t *testing.T) {
+	tests := []struct {
+		spec string
+		want []Attribute
+	}{
+		{"red,bold", []Attribute{FgRed, Bold}},
+		{"  Red  BOLD ", []Attribute{FgRed, Bold}},
+		{"hi-red, bg-white underline", []Attribute{FgHiRed, BgWhite, Underline}},
+		{"bg-hi-blue,crossed-out,double-underline", []Attribute{BgHiBlue, CrossedOut, DoubleUnderline}},
+		{"256:160", []Attribute{38, 5, 160}},
+		{"bg-256:21,ul-256:1", []Attribute{48, 5, 21, 58, 5, 1}},
+		{"rgb:255,0,0,bold", []Attribute{38, 2, 255, 0, 0, Bold}},
+		{"bg-rgb:1, 2, 3 ul-rgb:4,5,6", []Attribute{48, 2, 1, 2, 3, 58, 2, 4, 5, 6}},
+		{"53,200", []Attribute{Overline, 200}},
+		{"", []Attribute{}},
+	}
+
+	for i, tt := range tests {
+		c, err := ParseSpec(tt.spec)
+		if err != nil {
+			t.Errorf("[%d] unexpected error: %v", i, err)
+			continue
+		}
+		if got := c.Attributes(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("[%d] want: %v, got: %v", i, tt.want, got)
+		}
+	}
+}
+
+func TestParseSpecErrors(t *testing.T) {
+	tests := []string{
+		"purple",
+		"fg-red",
+		"256:",
+		"256:256",
+		"bg-256:x",
+		"rgb:1,2",
+		"rgb:1,2,300",
+		"bg-rgb:,1,2",
+		"38",
+		"-1",
+	}
+
+	for i, spec := range tests {
+		if _, err := ParseSpec(spec); err == nil {
+			t.Errorf("[%d] want error for %q, got nil", i, spec)
+		}
+	}
+}
+
+func TestColourSpec(t *testing.T) {
+	tests := []struct {
+		c    *Colour
+		want string
+	}{
+		{New(FgRed, Bold, Underline), "red,bold,underline"},
+		{New(BgHiWhite, CrossedOut, CurlyUnderline), "bg-hi-white,crossed-out,curly-underline"},
+		{NewFg256(160).AddBg256(21).UnderlineColour256(1), "256:160,bg-256:21,ul-256:1"},
+		{RGB(255, 0, 0).AddBgRGB(1, 2, 3).UnderlineColourRGB(4, 5, 6), "rgb:255,0,0,bg-rgb:1,2,3,ul-rgb:4,5,6"},
+		{New(Bold, 200), "bold,200"},
+		{New(), ""},
+	}
+
+	for i, tt := range tests {
+		got := tt.c.Spec()
+		if got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+
+		c, err := ParseSpec(got)
+		if err != nil {
+			t.Errorf("[%d] unexpected error: %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(c.Attributes(), tt.c.Attributes()) {
+			t.Errorf("[%d] want: %v, got: %v", i, tt.c.Attributes(), c.Attributes())
+		}
+	}
+}
+
+func TestColourSpecInexpressible(t *testing.T) {
+	tests := []struct {
+		c    *Colour
+		want string
+	}{
+		{New(Bold, extendedFg, extendedIndexed), "bold,38,blink-slow"},
+		{New(extendedBg), "48"},
+		{New(FgRed, 300), "red,300"},
+	}
+
+	for i, tt := range tests {
+		got := tt.c.Spec()
+		if got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+		if _, err := ParseSpec(got); err == nil {
+			t.Errorf("[%d] want error parsing %q, got nil", i, got)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package colour
+
+import "strings"
+
+// Stack nests colours, for example while rendering styled regions inside
+// each other. Pushed colours add to the ones below them, and popping one
+// restores exactly the style in effect before it was pushed rather than
+// resetting everything. The zero value is an empty stack ready to use.
+type Stack struct {
+	colours []*Colour
+}
+
+// Push puts c on top of the stack and returns the sequence applying it. The
+// sequence is empty if colour is disabled for c or c has no attributes.
+func (s *Stack) Push(c *Colour) string {
+	s.colours = append(s.colours, c)
+	return setSequence(c)
+}
+
+// Pop removes the colour on top of the stack and returns the sequence
+// restoring the style of the colours left: a reset followed by each of them
+// in the order they were pushed, or just a reset when the stack becomes
+// empty. If the popped colour applied nothing, because colour is disabled
+// for it or it has no attributes, there is nothing to restore and the
+// sequence is empty, as it is when popping an empty stack.
+func (s *Stack) Pop() string {
+	if len(s.colours) == 0 {
+		return ""
+	}
+	top := s.colours[len(s.colours)-1]
+	s.colours = s.colours[:len(s.colours)-1]
+
+	if setSequence(top) == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(resetSequence)
+	for _, c := range s.colours {
+		b.WriteString(setSequence(c))
+	}
+
+	return b.String()
+}
+
+// Len returns the number of colours on the stack.
+func (s *Stack) Len() int {
+	return len(s.colours)
+}
+
+// setSequence returns the sequence applying c on top of the colours below
+// it, or an empty string if colour is disabled for c or c has no attributes.
+// Unlike the prefix of c, it never starts with a reset, whatever
+// ResetBefore, as that would drop the colours below.
+func setSequence(c *Colour) string {
+	if c.isNoColourSet() || c.IsEmpty() {
+		return ""
+	}
+
+	return c.format()
+}
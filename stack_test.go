@@ -0,0 +1,83 @@
+package colour
+
+import "testing"
+
+func TestStack(t *testing.T) {
+	NoColour = false
+
+	plain := New(FgGreen)
+	plain.DisableColour()
+
+	var s Stack
+	steps := []struct {
+		got, want string
+	}{
+		{s.Pop(), ""},
+		{s.Push(New(FgBlue)), "\x1b[34m"},
+		{s.Push(New(Bold)), "\x1b[1m"},
+		{s.Push(New(FgRed)), "\x1b[31m"},
+		{s.Push(plain), ""},
+		{s.Pop(), ""},
+		{s.Push(New()), ""},
+		{s.Pop(), ""},
+		{s.Pop(), "\x1b[0m\x1b[34m\x1b[1m"},
+		{s.Pop(), "\x1b[0m\x1b[34m"},
+		{s.Pop(), "\x1b[0m"},
+		{s.Pop(), ""},
+	}
+
+	for i, step := range steps {
+		if step.got != step.want {
+			t.Errorf("[%d] want: %q, got: %q", i, step.want, step.got)
+		}
+	}
+	if s.Len() != 0 {
+		t.Errorf("want empty stack, got: %d", s.Len())
+	}
+
+	NoColour = true
+	defer func() { NoColour = false }()
+	if got := s.Push(New(FgRed)) + s.Pop(); got != "" {
+		t.Errorf("want: %q, got: %q", "", got)
+	}
+}
+
+func TestStackEmptyColour(t *testing.T) {
+	NoColour = false
+
+	var s Stack
+	if got, want := s.Push(New(Bold))+s.Push(New())+s.Push(New(FgRed)), "\x1b[1m\x1b[31m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+	if got, want := s.Pop(), "\x1b[0m\x1b[1m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+}
+
+func TestStackForcedColour(t *testing.T) {
+	NoColour = true
+	defer func() { NoColour = false }()
+
+	red := New(FgRed)
+	red.EnableColour()
+	blue := New(FgBlue)
+	blue.EnableColour()
+
+	var s Stack
+	steps := []struct {
+		got, want string
+	}{
+		{s.Push(blue), "\x1b[34m"},
+		{s.Push(New(Underline)), ""},
+		{s.Push(red), "\x1b[31m"},
+		{s.Pop(), "\x1b[0m\x1b[34m"},
+		{s.Pop(), ""},
+		{s.Pop(), "\x1b[0m"},
+	}
+
+	for i, step := range steps {
+		if step.got != step.want {
+			t.Errorf("[%d] want: %q, got: %q", i, step.want, step.got)
+		}
+	}
+}
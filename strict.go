@@ -0,0 +1,116 @@
+package colour
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// StrictFormat makes Sprintf, Printf, Fprintf and FprintfCtx, the functions
+// returned by SprintfFunc, PrintfFunc, FprintfFunc and BoundFprintfFunc, and
+// the print and String helpers such as Red, RedString and Alert, check that
+// the number of arguments matches the verbs of the format. On a mismatch the
+// error message replaces the output, instead of fmt's "%!(EXTRA ...)" and
+// "%!d(MISSING)" markers, which would otherwise end up coloured: Printf,
+// Fprintf and FprintfCtx print nothing and return the error, the Sprintf
+// functions and String helpers return the message, and the others, which
+// cannot return an error, print it uncoloured. This is useful to catch
+// logging bugs early.
+var StrictFormat = false
+
+// strictFormatError returns the error of checkFormat if StrictFormat is set,
+// nil otherwise.
+func strictFormatError(format string, n int) error {
+	if !StrictFormat {
+		return nil
+	}
+
+	return checkFormat(format, n)
+}
+
+// writeFormatError writes the message of strictFormatError followed by end
+// to w, for functions unable to return it. It reports whether there was an
+// error.
+func writeFormatError(w io.Writer, format string, n int, end string) bool {
+	err := strictFormatError(format, n)
+	if err == nil {
+		return false
+	}
+
+	io.WriteString(w, err.Error()+end)
+	return true
+}
+
+// checkFormat returns an error if format does not use exactly n arguments,
+// following fmt's rules for flags, widths and precisions given by '*', and
+// explicit argument indexes. As with fmt, extra arguments are allowed once
+// an index was given.
+func checkFormat(format string, n int) error {
+	argNum := 0
+	reordered := false
+
+	// index consumes an explicit argument index such as "[2]" at format[i].
+	index := func(i int) int {
+		if i >= len(format) || format[i] != '[' {
+			return i
+		}
+		j := strings.IndexByte(format[i:], ']')
+		if j < 0 {
+			return i
+		}
+		if v, err := strconv.Atoi(format[i+1 : i+j]); err == nil && v > 0 {
+			argNum = v - 1
+			reordered = true
+		}
+		return i + j + 1
+	}
+
+	// arg consumes a width or precision at format[i], which uses an
+	// argument if given by '*'.
+	arg := func(i int) int {
+		i = index(i)
+		if i < len(format) && format[i] == '*' {
+			argNum++
+			return i + 1
+		}
+		for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+			i++
+		}
+		return i
+	}
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+
+		i++
+		for i < len(format) && strings.IndexByte("+-# 0", format[i]) >= 0 {
+			i++
+		}
+		i = arg(i)
+		if i < len(format) && format[i] == '.' {
+			i = arg(i + 1)
+		}
+		i = index(i)
+
+		if i >= len(format) {
+			return fmt.Errorf("colour: format %q ends without a verb", format)
+		}
+		if format[i] == '%' {
+			continue
+		}
+
+		argNum++
+		if argNum > n {
+			return fmt.Errorf("colour: missing argument for format %q", format)
+		}
+	}
+
+	if !reordered && argNum < n {
+		return fmt.Errorf("colour: too many arguments for format %q, got %d", format, n)
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package colour
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCheckFormat(t *testing.T) {
+	tests := []struct {
+		format string
+		n      int
+		ok     bool
+	}{
+		{"plain", 0, true},
+		{"%d%%", 1, true},
+		{"%s %v", 2, true},
+		{"%-8.3f", 1, true},
+		{"%*d", 2, true},
+		{"%.*f", 2, true},
+		{"%[2]d %[1]d", 2, true},
+		{"%[1]d", 3, true},
+		{"%d", 0, false},
+		{"%d", 2, false},
+		{"%*d", 1, false},
+		{"100%", 0, false},
+		{"plain", 1, false},
+		{"%[3]d", 2, false},
+	}
+
+	for i, tt := range tests {
+		if err := checkFormat(tt.format, tt.n); (err == nil) != tt.ok {
+			t.Errorf("[%d] %q with %d arguments: want ok %v, got: %v", i, tt.format, tt.n, tt.ok, err)
+		}
+	}
+}
+
+func TestStrictFormat(t *testing.T) {
+	NoColour = false
+	StrictFormat = true
+	defer func() { StrictFormat = false }()
+
+	c := New(FgRed)
+	format := "%d" // not a constant, keeping vet from flagging the mistakes
+	if got, want := c.Sprintf(format, 1), "\x1b[31m1\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+	if got, want := c.Sprintf(format, 1, 2), `colour: too many arguments for format "%d", got 2`; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+
+	var buf bytes.Buffer
+	if _, err := c.Fprintf(&buf, format+" %d", 1); err == nil {
+		t.Error("Fprintf with a missing argument returned no error")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Fprintf wrote %q despite the error", buf.String())
+	}
+}
+
+func TestStrictFormatFuncs(t *testing.T) {
+	NoColour = false
+	StrictFormat = true
+	defer func() { StrictFormat = false }()
+
+	oldOut, oldErr := GetOutput(), GetError()
+	defer func() {
+		SetOutput(oldOut)
+		SetError(oldErr)
+	}()
+
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	SetError(&buf)
+
+	c := New(FgRed)
+	format := "%d %d" // not a constant, keeping vet from flagging the mistakes
+	msg := `colour: missing argument for format "%d %d"`
+	tests := []struct {
+		print func()
+		want  string
+	}{
+		{func() { c.FprintfFunc()(&buf, format, 1) }, msg},
+		{func() { c.PrintfFunc()(format, 1) }, msg},
+		{func() { c.BoundFprintfFunc(&buf)(format, 1) }, msg},
+		{func() { Red(format, 1) }, msg + "\n"},
+		{func() { RedBold(format, 1) }, msg + "\n"},
+		{func() { Alert(format, 1) }, msg + "\n"},
+		{func() { buf.WriteString(RedString(format, 1)) }, msg},
+		{func() { Red(format, 1, 2) }, "\x1b[31m1 2\n\x1b[0m"},
+	}
+
+	for i, tt := range tests {
+		buf.Reset()
+		tt.print()
+		if got := buf.String(); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package colour
+
+import "io"
+
+// States of a stripper.
+const (
+	stripText   = iota
+	stripEscape // after ESC
+	stripCSI    // inside ESC [ ...
+	stripString // inside a string sequence such as OSC
+	stripStringEscape
+)
+
+// stripper removes escape sequences from a stream of bytes, recognising the
+// same sequences as Strip. It keeps the state of a sequence split across
+// chunks, so it needs no buffering.
+type stripper struct {
+	state int
+}
+
+// filter removes the escape sequences from b in place and returns the bytes
+// left.
+func (s *stripper) filter(b []byte) []byte {
+	n := 0
+	for _, c := range b {
+		if s.step(c) {
+			b[n] = c
+			n++
+		}
+	}
+
+	return b[:n]
+}
+
+// step advances the state by c and reports whether c is text to be kept.
+func (s *stripper) step(c byte) bool {
+	switch s.state {
+	case stripEscape:
+		switch c {
+		case '[':
+			s.state = stripCSI
+		case ']', 'P', '_', '^', 'X':
+			s.state = stripString
+		default:
+			s.state = stripText
+		}
+		return false
+
+	case stripCSI:
+		switch {
+		case c >= 0x40 && c <= 0x7e:
+			s.state = stripText
+			return false
+		case c < 0x20:
+			// malformed, the control byte is not part of the sequence
+			s.state = stripText
+			return s.step(c)
+		}
+		return false
+
+	case stripString:
+		switch c {
+		case '\a':
+			s.state = stripText
+		case escape[0]:
+			s.state = stripStringEscape
+		}
+		return false
+
+	case stripStringEscape:
+		if c == '\\' {
+			s.state = stripText
+			return false
+		}
+		s.state = stripString
+		return s.step(c)
+	}
+
+	if c == escape[0] {
+		s.state = stripEscape
+		return false
+	}
+
+	return true
+}
+
+type stripReader struct {
+	r io.Reader
+	s stripper
+}
+
+// NewStripReader returns a reader that reads from r with all escape
+// sequences removed, such as SGR colours, other CSI sequences and hyperlinks,
+// for example to store the output of a command in a log file. Sequences split
+// across reads are recognised. Bytes are stripped in the buffer given to
+// Read, so no further memory is allocated.
+func NewStripReader(r io.Reader) io.Reader {
+	return &stripReader{r: r}
+}
+
+func (sr *stripReader) Read(p []byte) (int, error) {
+	for {
+		n, err := sr.r.Read(p)
+		n = len(sr.s.filter(p[:n]))
+		if n > 0 || err != nil || len(p) == 0 {
+			return n, err
+		}
+	}
+}
+
+type stripWriter struct {
+	w   io.Writer
+	s   stripper
+	buf []byte
+}
+
+// NewStripWriter returns a writer that writes to w with all escape sequences
+// removed, so output of code writing colours can be sent to a file or other
+// destination without colour support. Sequences split across writes are
+// recognised. Close closes w if it is an io.Closer, a sequence left
+// incomplete at that point is dropped.
+func NewStripWriter(w io.Writer) io.WriteCloser {
+	return &stripWriter{w: w}
+}
+
+func (sw *stripWriter) Write(p []byte) (int, error) {
+	sw.buf = sw.s.filter(append(sw.buf[:0], p...))
+	if len(sw.buf) > 0 {
+		if _, err := sw.w.Write(sw.buf); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+func (sw *stripWriter) Close() error {
+	sw.s = stripper{}
+	if c, ok := sw.w.(io.Closer); ok {
+		return c.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package colour
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// stripInputs are stripped by the streaming strippers and compared with
+// Strip.
+var stripInputs = []string{
+	"",
+	"plain",
+	"\x1b[31mred\x1b[0m",
+	"\x1b[1;4;31mbold\x1b[0m \x1b[mnext",
+	"\x1b[38;2;255;128;0mtrue\x1b[48;2;0;0;0m\x1b[0mcolour",
+	"\x1b[32mgrüße, 世界 😀\x1b[0m",
+	"\x1b[2J\x1b[Hcleared",
+	"\x1b]8;;http://example.com\x1b\\link\x1b]8;;\x1b\\",
+	"\x1b]0;ti\x1bxtle\atext",
+	"\x1b[4:3mcurly\x1b[0m",
+	"\x1b[3\nmalformed",
+	"\x1b[3\x1b[31mred",
+	"trailing\x1b[3",
+	"\x1b7saved\x1b8",
+}
+
+func TestStripReader(t *testing.T) {
+	for i, s := range stripInputs {
+		want := Strip(s)
+
+		got, err := ioutil.ReadAll(NewStripReader(strings.NewReader(s)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("[%d] want: %q, got: %q", i, want, got)
+		}
+
+		got, err = ioutil.ReadAll(NewStripReader(iotest.OneByteReader(strings.NewReader(s))))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("[%d] one byte reads want: %q, got: %q", i, want, got)
+		}
+	}
+}
+
+type closeBuffer struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (b *closeBuffer) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestStripWriter(t *testing.T) {
+	for i, s := range stripInputs {
+		want := Strip(s)
+
+		var buf closeBuffer
+		sw := NewStripWriter(&buf)
+		if n, err := io.WriteString(sw, s); err != nil || n != len(s) {
+			t.Fatalf("[%d] wrote %d of %d bytes: %v", i, n, len(s), err)
+		}
+		if got := buf.String(); got != want {
+			t.Errorf("[%d] want: %q, got: %q", i, want, got)
+		}
+
+		buf.Reset()
+		sw = NewStripWriter(&buf)
+		for j := 0; j < len(s); j++ {
+			if _, err := io.WriteString(sw, s[j:j+1]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if got := buf.String(); got != want {
+			t.Errorf("[%d] one byte writes want: %q, got: %q", i, want, got)
+		}
+
+		if err := sw.Close(); err != nil || !buf.closed {
+			t.Errorf("[%d] not closed: %v", i, err)
+		}
+	}
+
+	// the input is left untouched
+	p := []byte("\x1b[31mred")
+	if _, err := NewStripWriter(ioutil.Discard).Write(p); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(p), "\x1b[31mred"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+	if err := NewStripWriter(ioutil.Discard).Close(); err != nil {
+		t.Error(err)
+	}
+}
+
+func BenchmarkStripReader(b *testing.B) {
+	s := strings.Repeat("\x1b[31mred\x1b[0m and plain text ", 100)
+	r := strings.NewReader(s)
+	sr := NewStripReader(r)
+	buf := make([]byte, 512)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r.Reset(s)
+		for {
+			if _, err := sr.Read(buf); err != nil {
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package colour
+
+// Style returns a new colour object without attributes, to be built up with
+// chained methods as an alternative to listing attributes in New:
+//
+//	colour.Style().Fg(colour.FgRed).Bg(colour.BgWhite).Bold().Underline()
+func Style() *Colour {
+	return New()
+}
+
+// Fg sets the foreground to fg, replacing any foreground set before,
+// including extended ones. It returns c for chaining.
+func (c *Colour) Fg(fg Attribute) *Colour {
+	return c.replaceKind(0, fg)
+}
+
+// Bg sets the background to bg, replacing any background set before,
+// including extended ones. It returns c for chaining.
+func (c *Colour) Bg(bg Attribute) *Colour {
+	return c.replaceKind(1, bg)
+}
+
+// Bold adds bold text unless already set and returns c for chaining.
+func (c *Colour) Bold() *Colour {
+	return c.addOnce(Bold)
+}
+
+// Faint adds faint text unless already set and returns c for chaining.
+func (c *Colour) Faint() *Colour {
+	return c.addOnce(Faint)
+}
+
+// Italic adds italic text unless already set and returns c for chaining.
+func (c *Colour) Italic() *Colour {
+	return c.addOnce(Italic)
+}
+
+// Underline adds underlined text unless already set and returns c for
+// chaining.
+func (c *Colour) Underline() *Colour {
+	return c.addOnce(Underline)
+}
+
+// Reverse adds reverse video, swapping foreground and background, unless
+// already set and returns c for chaining.
+func (c *Colour) Reverse() *Colour {
+	return c.addOnce(ReverseVideo)
+}
+
+// CrossedOut adds crossed out text unless already set and returns c for
+// chaining.
+func (c *Colour) CrossedOut() *Colour {
+	return c.addOnce(CrossedOut)
+}
+
+// addOnce adds a unless c already holds it.
+func (c *Colour) addOnce(a Attribute) *Colour {
+	if c.hasParam(basicParam(a)) {
+		return c
+	}
+
+	return c.addParam(basicParam(a))
+}
+
+// replaceKind removes the parameters of kind k, as returned by colourKind,
+// and adds a.
+func (c *Colour) replaceKind(k int, a Attribute) *Colour {
+	c.mu.Lock()
+	params := make([]param, 0, len(c.params)+1)
+	for _, p := range c.params {
+		if colourKind(p.attrs()) != k {
+			params = append(params, p)
+		}
+	}
+	c.params = append(params, basicParam(a))
+	c.gen++
+	c.mu.Unlock()
+
+	return c
+}
@@ -0,0 +1,26 @@
+package colour
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStyle(t *testing.T) {
+	tests := []struct {
+		c    *Colour
+		want []Attribute
+	}{
+		{Style().Fg(FgRed).Bg(BgWhite).Bold().Underline(), []Attribute{FgRed, BgWhite, Bold, Underline}},
+		{Style().Fg(FgRed).Fg(FgBlue), []Attribute{FgBlue}},
+		{NewFg256(5).Bold().Fg(FgGreen), []Attribute{Bold, FgGreen}},
+		{BgRGB(1, 2, 3).Bg(BgHiBlack).Bg(BgBlack), []Attribute{BgBlack}},
+		{Style().Bold().Bold().Italic().Faint().Reverse().CrossedOut(), []Attribute{Bold, Italic, Faint, ReverseVideo, CrossedOut}},
+		{Style().Fg(FgRed).Bg(BgBlue).Fg(FgYellow), []Attribute{BgBlue, FgYellow}},
+	}
+
+	for i, tt := range tests {
+		if got := tt.c.Attributes(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("[%d] want: %v, got: %v", i, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package colour
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// Table renders rows of cells in aligned columns. Columns are sized by the
+// visible width of their content, so cells may contain coloured text and wide
+// runes.
+type Table struct {
+	headers     []string
+	rows        [][]string
+	headerStyle *Colour
+	styles      map[int]*Colour
+}
+
+// NewTable returns a table with the given column headers. Headers are shown
+// in bold unless changed with SetHeaderStyle.
+func NewTable(headers []string) *Table {
+	return &Table{
+		headers:     headers,
+		headerStyle: New(Bold),
+		styles:      make(map[int]*Colour),
+	}
+}
+
+// AddRow appends a row of cells. Rows may hold fewer or more cells than there
+// are headers, missing cells are left empty.
+func (t *Table) AddRow(cells ...string) *Table {
+	t.rows = append(t.rows, cells)
+	return t
+}
+
+// SetHeaderStyle sets the colour used for the headers, nil leaves them plain.
+func (t *Table) SetHeaderStyle(c *Colour) *Table {
+	t.headerStyle = c
+	return t
+}
+
+// SetColumnStyle sets the colour applied to the cells of column i, counting
+// from zero. The headers are not affected.
+func (t *Table) SetColumnStyle(i int, c *Colour) *Table {
+	t.styles[i] = c
+	return t
+}
+
+// Render writes the table to w, with the header separated from the rows by a
+// line and columns separated by " | ". Colour is left out whenever it is
+// disabled for the colours involved.
+func (t *Table) Render(w io.Writer) error {
+	cols := len(t.headers)
+	for _, row := range t.rows {
+		if len(row) > cols {
+			cols = len(row)
+		}
+	}
+	if cols == 0 {
+		return nil
+	}
+
+	widths := make([]int, cols)
+	measure := func(cells []string) {
+		for i, cell := range cells {
+			if n := visibleWidth(sanitize(cell)); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	measure(t.headers)
+	for _, row := range t.rows {
+		measure(row)
+	}
+
+	bw := bufio.NewWriter(w)
+
+	if len(t.headers) > 0 {
+		t.line(bw, widths, t.headers, func(int) *Colour { return t.headerStyle })
+
+		sep := make([]string, cols)
+		for i, n := range widths {
+			sep[i] = strings.Repeat("-", n)
+		}
+		bw.WriteString(strings.Join(sep, "-+-"))
+		bw.WriteByte('\n')
+	}
+
+	for _, row := range t.rows {
+		t.line(bw, widths, row, func(i int) *Colour { return t.styles[i] })
+	}
+
+	return bw.Flush()
+}
+
+// line writes a single row of cells, padding all but the last column.
+func (t *Table) line(w *bufio.Writer, widths []int, cells []string, style func(int) *Colour) {
+	for i, width := range widths {
+		var cell string
+		if i < len(cells) {
+			cell = styled(style(i), cells[i])
+		}
+
+		if i > 0 {
+			w.WriteString(" | ")
+		}
+		if i < len(widths)-1 {
+			cell = padRight(cell, width)
+		}
+		w.WriteString(cell)
+	}
+	w.WriteByte('\n')
+}
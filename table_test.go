@@ -0,0 +1,46 @@
+package colour
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTable(t *testing.T) {
+	NoColour = true
+	defer func() { NoColour = false }()
+
+	tbl := NewTable([]string{"NAME", "STATUS", "NOTE"})
+	tbl.AddRow("alpha", "ok")
+	tbl.AddRow("b", "\x1b[31mfailed\x1b[0m", "see 日本")
+	tbl.AddRow("", "", "", "extra")
+
+	var buf bytes.Buffer
+	if err := tbl.Render(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "" +
+		"NAME  | STATUS | NOTE     | \n" +
+		"------+--------+----------+------\n" +
+		"alpha | ok     |          | \n" +
+		"b     | \x1b[31mfailed\x1b[0m | see 日本 | \n" +
+		"      |        |          | extra\n"
+	if got := buf.String(); got != want {
+		t.Errorf("want:\n%s\ngot:\n%s", want, got)
+	}
+
+	NoColour = false
+	tbl = NewTable([]string{"A", "B"})
+	tbl.SetColumnStyle(1, New(FgGreen))
+	tbl.AddRow("x", "y")
+
+	buf.Reset()
+	tbl.Render(&buf)
+	want = "" +
+		"\x1b[1mA\x1b[0m | \x1b[1mB\x1b[0m\n" +
+		"--+--\n" +
+		"x | \x1b[32my\x1b[0m\n"
+	if got := buf.String(); got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+}
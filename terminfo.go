@@ -0,0 +1,118 @@
+package colour
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Magic numbers of compiled terminfo entries, the second one being the
+// extended format of ncurses 6.1 which stores numbers in 32 bits.
+const (
+	terminfoMagic   = 0432
+	terminfoMagic32 = 01036
+)
+
+// terminfoColors is the index of the colors capability among the numbers.
+const terminfoColors = 13
+
+// terminfoDirs returns the directories searched for compiled terminfo
+// entries, in the order used by ncurses.
+func terminfoDirs() []string {
+	var dirs []string
+	if d := os.Getenv("TERMINFO"); d != "" {
+		dirs = append(dirs, d)
+	}
+	if h := os.Getenv("HOME"); h != "" {
+		dirs = append(dirs, filepath.Join(h, ".terminfo"))
+	}
+	if ds := os.Getenv("TERMINFO_DIRS"); ds != "" {
+		for _, d := range strings.Split(ds, ":") {
+			if d == "" {
+				d = "/usr/share/terminfo"
+			}
+			dirs = append(dirs, d)
+		}
+	}
+
+	return append(dirs, "/etc/terminfo", "/lib/terminfo", "/usr/share/terminfo")
+}
+
+// terminfoColours returns the colors capability of the terminfo entry for
+// term, or 0 if the entry lacks the capability as terminals without colour
+// support do. The boolean is false if no entry is found or it cannot be
+// read.
+func terminfoColours(term string) (int, bool) {
+	if term == "" || strings.ContainsAny(term, `/\`) {
+		return 0, false
+	}
+
+	for _, dir := range terminfoDirs() {
+		// entries live in a directory named after their first letter, or its
+		// hexadecimal value on case insensitive file systems
+		for _, sub := range []string{term[:1], fmt.Sprintf("%x", term[0])} {
+			b, err := ioutil.ReadFile(filepath.Join(dir, sub, term))
+			if err != nil {
+				continue
+			}
+			return parseTerminfoColours(b)
+		}
+	}
+
+	return 0, false
+}
+
+// parseTerminfoColours reads the colors capability from a compiled terminfo
+// entry, see term(5). It returns 0 if the capability is absent and false if b
+// is not a valid entry.
+func parseTerminfoColours(b []byte) (int, bool) {
+	if len(b) < 12 {
+		return 0, false
+	}
+
+	header := func(i int) int {
+		return int(int16(binary.LittleEndian.Uint16(b[i*2:])))
+	}
+
+	numSize := 2
+	switch header(0) {
+	case terminfoMagic:
+	case terminfoMagic32:
+		numSize = 4
+	default:
+		return 0, false
+	}
+
+	namesSize, boolCount, numCount := header(1), header(2), header(3)
+	if namesSize < 0 || boolCount < 0 || numCount < 0 {
+		return 0, false
+	}
+	if numCount <= terminfoColors {
+		return 0, true
+	}
+
+	// numbers start on an even byte
+	off := 12 + namesSize + boolCount
+	off += off % 2
+	off += terminfoColors * numSize
+	if off+numSize > len(b) {
+		return 0, false
+	}
+
+	var n int
+	if numSize == 2 {
+		n = int(int16(binary.LittleEndian.Uint16(b[off:])))
+	} else {
+		n = int(int32(binary.LittleEndian.Uint32(b[off:])))
+	}
+
+	// negative values mark absent or cancelled capabilities
+	if n < 0 {
+		return 0, true
+	}
+
+	return n, true
+}
@@ -0,0 +1,105 @@
+package colour
+
+import (
+	"strings"
+	"sync"
+)
+
+// Theme maps semantic names such as "error" or "success" to colours, so an
+// application can refer to its colours by meaning and swap all of them at
+// once, for example between a dark and a light palette.
+type Theme map[string]*Colour
+
+// DefaultTheme is the theme in use until SetTheme is called.
+var DefaultTheme = Theme{
+	"fatal":   New(FgRed, Bold),
+	"error":   New(FgRed),
+	"warning": New(FgYellow),
+	"success": New(FgGreen),
+	"info":    New(FgCyan),
+	"debug":   New(FgHiBlack),
+	"trace":   New(FgHiBlack, Faint),
+	"heading": New(Bold),
+}
+
+// levelNames maps the common names of log levels to the theme names of
+// their colours.
+var levelNames = map[string]string{
+	"trace":   "trace",
+	"debug":   "debug",
+	"info":    "info",
+	"warn":    "warning",
+	"warning": "warning",
+	"error":   "error",
+	"err":     "error",
+	"fatal":   "fatal",
+	"panic":   "fatal",
+}
+
+var (
+	theme   = DefaultTheme.copy()
+	themeMu sync.RWMutex // protects theme
+)
+
+// SetTheme replaces the current theme with t. The map and its colours are
+// copied, later changes to t or the colours in it have no effect until
+// SetTheme is called again.
+func SetTheme(t Theme) {
+	c := t.copy()
+
+	themeMu.Lock()
+	defer themeMu.Unlock()
+	theme = c
+}
+
+// Named returns a copy of the colour registered for name in the current
+// theme, which can be changed without affecting the theme. If there is none,
+// a colour printing plain text is returned, so a missing entry never breaks
+// output.
+func Named(name string) *Colour {
+	themeMu.RLock()
+	c, ok := theme[name]
+	themeMu.RUnlock()
+
+	if !ok || c == nil {
+		c = New()
+		c.DisableColour()
+		return c
+	}
+
+	return c.Clone()
+}
+
+// ForLevel returns the colour for the log level with the given name, such as
+// "debug", "info", "warn" or "error", case insensitively:
+//
+//	colour.ForLevel("warn").Println(msg)
+//
+// The colours are taken from the current theme, "warn" and "warning" both
+// using the theme's "warning" entry and "panic" using "fatal", so they can be
+// changed with SetTheme. For unknown levels a colour printing plain text is
+// returned.
+func ForLevel(name string) *Colour {
+	themeName, ok := levelNames[strings.ToLower(name)]
+	if !ok {
+		c := New()
+		c.DisableColour()
+		return c
+	}
+
+	return Named(themeName)
+}
+
+// copy returns a deep copy of t, so neither changes to t nor to the colours
+// returned by Named affect one another.
+func (t Theme) copy() Theme {
+	c := make(Theme, len(t))
+	for name, v := range t {
+		if v != nil {
+			v = v.Clone()
+		}
+		c[name] = v
+	}
+
+	return c
+}
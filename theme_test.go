@@ -0,0 +1,83 @@
+package colour
+
+import "testing"
+
+func TestTheme(t *testing.T) {
+	NoColour = false
+	defer SetTheme(DefaultTheme)
+
+	if got, want := Named("error").Sprint("x"), "\x1b[31mx\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+
+	light := Theme{
+		"error": New(FgHiRed, Bold),
+		"nil":   nil,
+	}
+	SetTheme(light)
+	light["error"] = New(FgBlue)
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"error", "\x1b[91;1mx\x1b[0m"},
+		{"success", "x"},
+		{"nil", "x"},
+	}
+
+	for i, tt := range tests {
+		if got := Named(tt.name).Sprint("x"); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+	}
+}
+
+func TestThemeCopies(t *testing.T) {
+	NoColour = false
+	defer SetTheme(DefaultTheme)
+
+	Named("error").Add(Bold)
+	ForLevel("error").Add(Underline)
+	if got, want := Named("error").Sprint("x"), "\x1b[31mx\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+	if got, want := DefaultTheme["error"].Sprint("x"), "\x1b[31mx\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+
+	warning := New(FgYellow)
+	SetTheme(Theme{"warning": warning})
+	warning.Add(Bold)
+	if got, want := Named("warning").Sprint("x"), "\x1b[33mx\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+}
+
+func TestForLevel(t *testing.T) {
+	NoColour = false
+	defer SetTheme(DefaultTheme)
+
+	tests := []struct {
+		level string
+		want  string
+	}{
+		{"info", "\x1b[36mx\x1b[0m"},
+		{"WARN", "\x1b[33mx\x1b[0m"},
+		{"warning", "\x1b[33mx\x1b[0m"},
+		{"panic", "\x1b[31;1mx\x1b[0m"},
+		{"heading", "x"},
+		{"unknown", "x"},
+	}
+
+	for i, tt := range tests {
+		if got := ForLevel(tt.level).Sprint("x"); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+	}
+
+	SetTheme(Theme{"warning": New(FgMagenta)})
+	if got, want := ForLevel("warn").Sprint("x"), "\x1b[35mx\x1b[0m"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+}
@@ -0,0 +1,98 @@
+package colour
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// colonParam marks an attribute holding a parameter with a colon separated
+// sub-parameter, such as "4:3". The parameter is kept in the second byte and
+// the sub-parameter in the first.
+const colonParam Attribute = 1 << 16
+
+// Underline styles. DoubleUnderline is SGR 21, the others are sent in the
+// colon form "4:n" and not understood by every terminal. All of them fall
+// back to a plain underline where extended underlines are not supported, see
+// SetExtendedUnderline.
+const (
+	DoubleUnderline Attribute = 21
+	CurlyUnderline  Attribute = colonParam | Underline<<8 | 3
+	DottedUnderline Attribute = colonParam | Underline<<8 | 4
+	DashedUnderline Attribute = colonParam | Underline<<8 | 5
+)
+
+var (
+	extendedUnderlineOnce sync.Once
+	extendedUnderline     int32 // non-zero if supported, valid once extendedUnderlineOnce is done
+)
+
+// GetExtendedUnderline reports whether the double, curly, dotted and dashed
+// underline styles are sent as is. When false they are replaced by a plain
+// underline. It is detected from the environment on first use unless set
+// with SetExtendedUnderline.
+func GetExtendedUnderline() bool {
+	extendedUnderlineOnce.Do(func() {
+		storeExtendedUnderline(detectExtendedUnderline())
+	})
+
+	return atomic.LoadInt32(&extendedUnderline) != 0
+}
+
+// SetExtendedUnderline overrides the detected support for extended underline
+// styles.
+func SetExtendedUnderline(enable bool) {
+	extendedUnderlineOnce.Do(func() {})
+	storeExtendedUnderline(enable)
+}
+
+func storeExtendedUnderline(enable bool) {
+	var v int32
+	if enable {
+		v = 1
+	}
+	atomic.StoreInt32(&extendedUnderline, v)
+}
+
+// detectExtendedUnderline reports whether the terminal is known to support
+// extended underline styles. There is no reliable way to query this, so only
+// terminals identifying themselves are recognised: kitty, WezTerm, foot and
+// VTE based terminals from version 0.52.
+func detectExtendedUnderline() bool {
+	term := os.Getenv("TERM")
+	for _, t := range []string{"kitty", "wezterm", "foot"} {
+		if strings.Contains(term, t) {
+			return true
+		}
+	}
+	if os.Getenv("TERM_PROGRAM") == "WezTerm" {
+		return true
+	}
+	if v, err := strconv.Atoi(os.Getenv("VTE_VERSION")); err == nil && v >= 5200 {
+		return true
+	}
+
+	return false
+}
+
+// isExtendedUnderline reports whether a is one of the extended underline
+// styles.
+func isExtendedUnderline(a Attribute) bool {
+	switch a {
+	case DoubleUnderline, CurlyUnderline, DottedUnderline, DashedUnderline:
+		return true
+	}
+
+	return false
+}
+
+// attrParam returns a as it appears in an SGR sequence.
+func attrParam(a Attribute) string {
+	if a&colonParam != 0 {
+		return strconv.Itoa(int(a>>8&0xff)) + ":" + strconv.Itoa(int(a&0xff))
+	}
+
+	return strconv.Itoa(int(a))
+}
@@ -0,0 +1,57 @@
+package colour
+
+import "testing"
+
+func TestExtendedUnderline(t *testing.T) {
+	NoColour = false
+	defer SetExtendedUnderline(GetExtendedUnderline())
+	defer SetLevel(GetLevel())
+	SetLevel(Level256)
+
+	tests := []struct {
+		c        *Colour
+		extended bool
+		want     string
+	}{
+		{New(DoubleUnderline), true, "\x1b[21mx\x1b[0m"},
+		{New(CurlyUnderline), true, "\x1b[4:3mx\x1b[0m"},
+		{New(DottedUnderline), true, "\x1b[4:4mx\x1b[0m"},
+		{New(FgRed, DashedUnderline), true, "\x1b[31;4:5mx\x1b[0m"},
+		{New(DoubleUnderline), false, "\x1b[4mx\x1b[0m"},
+		{New(FgRed, CurlyUnderline), false, "\x1b[31;4mx\x1b[0m"},
+		// palette entry 21 is not a double underline
+		{NewFg256(21), false, "\x1b[38;5;21mx\x1b[0m"},
+	}
+
+	for i, tt := range tests {
+		SetExtendedUnderline(tt.extended)
+		if got := tt.c.Sprint("x"); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+	}
+}
+
+func TestDetectExtendedUnderline(t *testing.T) {
+	tests := []struct {
+		term, vte string
+		want      bool
+	}{
+		{"xterm-256color", "", false},
+		{"xterm-kitty", "", true},
+		{"foot", "", true},
+		{"xterm-256color", "5002", false},
+		{"xterm-256color", "6003", true},
+	}
+
+	for i, tt := range tests {
+		restore := setenv("TERM", strPtr(tt.term))
+		restoreVTE := setenv("VTE_VERSION", strPtr(tt.vte))
+		restoreProg := setenv("TERM_PROGRAM", nil)
+		if got := detectExtendedUnderline(); got != tt.want {
+			t.Errorf("[%d] want: %t, got: %t", i, tt.want, got)
+		}
+		restoreProg()
+		restoreVTE()
+		restore()
+	}
+}
@@ -0,0 +1,52 @@
+package colour
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Value pairs a value with the colour it is printed in. It implements
+// fmt.Formatter, so it can be passed to any fmt function:
+//
+//	fmt.Printf("%-8s|\n", colour.Value{C: red, V: "hi"})
+//
+// The verb, flags and precision format V as usual and the result is coloured
+// with C, which may be nil to leave it plain. The width is applied to the
+// visible content and padded with spaces outside of the colour, unless the 0
+// flag pads it with zeros.
+type Value struct {
+	C *Colour
+	V interface{}
+}
+
+// Format implements fmt.Formatter.
+func (v Value) Format(f fmt.State, verb rune) {
+	width, hasWidth := f.Width()
+	zero := f.Flag('0') && !f.Flag('-')
+
+	directive := "%"
+	for _, flag := range "+# " {
+		if f.Flag(int(flag)) {
+			directive += string(flag)
+		}
+	}
+	if zero && hasWidth {
+		directive += "0" + strconv.Itoa(width)
+	}
+	if prec, ok := f.Precision(); ok {
+		directive += "." + strconv.Itoa(prec)
+	}
+	directive += string(verb)
+
+	s := styled(v.C, fmt.Sprintf(directive, v.V))
+	if hasWidth && !zero {
+		if f.Flag('-') {
+			s = padRight(s, width)
+		} else {
+			s = padLeft(s, width)
+		}
+	}
+
+	io.WriteString(f, s)
+}
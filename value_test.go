@@ -0,0 +1,34 @@
+package colour
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestValueFormat(t *testing.T) {
+	NoColour = false
+	red := New(FgRed)
+
+	tests := []struct {
+		got, want string
+	}{
+		{fmt.Sprintf("%s", Value{red, "hi"}), "\x1b[31mhi\x1b[0m"},
+		{fmt.Sprintf("%v", Value{red, 42}), "\x1b[31m42\x1b[0m"},
+		{fmt.Sprintf("%5s|", Value{red, "hi"}), "   \x1b[31mhi\x1b[0m|"},
+		{fmt.Sprintf("%-5s|", Value{red, "hi"}), "\x1b[31mhi\x1b[0m   |"},
+		{fmt.Sprintf("%.1s", Value{red, "hi"}), "\x1b[31mh\x1b[0m"},
+		{fmt.Sprintf("%6.2f", Value{red, 3.14159}), "  \x1b[31m3.14\x1b[0m"},
+		{fmt.Sprintf("%05d", Value{red, 42}), "\x1b[31m00042\x1b[0m"},
+		{fmt.Sprintf("%+d", Value{red, 42}), "\x1b[31m+42\x1b[0m"},
+		{fmt.Sprintf("%#x", Value{red, 255}), "\x1b[31m0xff\x1b[0m"},
+		{fmt.Sprintf("%q", Value{red, "a"}), "\x1b[31m\"a\"\x1b[0m"},
+		{fmt.Sprintf("%4s", Value{nil, "世"}), "  世"},
+		{fmt.Sprintf("%d", Value{nil, "x"}), "%!d(string=x)"},
+	}
+
+	for i, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, tt.got)
+		}
+	}
+}
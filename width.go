@@ -0,0 +1,465 @@
+package colour
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// wideRanges lists the code points rendered in two terminal cells, mostly
+// the East Asian wide and fullwidth blocks plus emoji.
+var wideRanges = []struct{ lo, hi rune }{
+	{0x1100, 0x115f},
+	{0x231a, 0x231b},
+	{0x2329, 0x232a},
+	{0x23e9, 0x23ec},
+	{0x23f0, 0x23f0},
+	{0x23f3, 0x23f3},
+	{0x25fd, 0x25fe},
+	{0x2614, 0x2615},
+	{0x2648, 0x2653},
+	{0x26a1, 0x26a1},
+	{0x26aa, 0x26ab},
+	{0x26bd, 0x26be},
+	{0x26c4, 0x26c5},
+	{0x26d4, 0x26d4},
+	{0x26ea, 0x26ea},
+	{0x26f2, 0x26f5},
+	{0x26fa, 0x26fd},
+	{0x2705, 0x2705},
+	{0x270a, 0x270b},
+	{0x2728, 0x2728},
+	{0x274c, 0x274c},
+	{0x2753, 0x2755},
+	{0x2757, 0x2757},
+	{0x2795, 0x2797},
+	{0x27b0, 0x27b0},
+	{0x27bf, 0x27bf},
+	{0x2b1b, 0x2b1c},
+	{0x2b50, 0x2b55},
+	{0x2e80, 0x303e},
+	{0x3041, 0x33ff},
+	{0x3400, 0x4dbf},
+	{0x4e00, 0x9fff},
+	{0xa000, 0xa4cf},
+	{0xa960, 0xa97f},
+	{0xac00, 0xd7a3},
+	{0xf900, 0xfaff},
+	{0xfe10, 0xfe19},
+	{0xfe30, 0xfe6f},
+	{0xff00, 0xff60},
+	{0xffe0, 0xffe6},
+	{0x16fe0, 0x16fe4},
+	{0x17000, 0x18cff},
+	{0x1b000, 0x1b2ff},
+	{0x1f004, 0x1f004},
+	{0x1f0cf, 0x1f0cf},
+	{0x1f18e, 0x1f18e},
+	{0x1f191, 0x1f19a},
+	{0x1f200, 0x1f251},
+	{0x1f300, 0x1f64f},
+	{0x1f680, 0x1f6ff},
+	{0x1f7e0, 0x1f7eb},
+	{0x1f90c, 0x1f9ff},
+	{0x1fa70, 0x1faff},
+	{0x20000, 0x2fffd},
+	{0x30000, 0x3fffd},
+}
+
+// runeWidth returns the number of terminal cells used to display r.
+func runeWidth(r rune) int {
+	switch {
+	case r < 0x20 || r == 0x7f:
+		return 0
+	case r < 0x300:
+		return 1
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	}
+
+	for _, w := range wideRanges {
+		if r < w.lo {
+			break
+		}
+		if r <= w.hi {
+			return 2
+		}
+	}
+
+	return 1
+}
+
+// isSGR reports whether the escape sequence seq is an SGR sequence.
+func isSGR(seq string) bool {
+	return len(seq) > 2 && seq[1] == '[' && seq[len(seq)-1] == 'm'
+}
+
+// sgrState tracks the SGR sequences in effect while scanning a string, so a
+// style interrupted by a cut can be opened again afterwards.
+type sgrState struct {
+	active string
+}
+
+func (st *sgrState) update(seq string) {
+	if !isSGR(seq) {
+		return
+	}
+
+	params := parseParams(seq[2 : len(seq)-1])
+	switch {
+	case params[0] != 0:
+		st.active += seq
+	case len(params) == 1:
+		st.active = ""
+	default:
+		st.active = seq
+	}
+}
+
+// feed updates the state with every SGR sequence in s.
+func (st *sgrState) feed(s string) {
+	for i := 0; i < len(s); i++ {
+		if s[i] != escape[0] {
+			continue
+		}
+		l := escapeLen(s[i:])
+		if l < 0 {
+			return
+		}
+		st.update(s[i : i+l])
+		i += l - 1
+	}
+}
+
+// Columns splits s into segments of the given visible widths, for example to
+// lay out a long coloured line in fixed columns. Each segment opens the style
+// in effect at its start and is closed with a reset, so it renders correctly
+// on its own. Escape sequences and runes are never split: a wide rune that
+// does not fit is moved to the next segment. Segments are padded with spaces
+// when s is too short to fill them, and anything left over after the last
+// width is dropped.
+func Columns(s string, widths []int) []string {
+	cols := make([]string, len(widths))
+
+	var st sgrState
+	i := 0
+	for n, width := range widths {
+		var b strings.Builder
+		b.WriteString(st.active)
+		open := st.active != ""
+		w := 0
+
+		for i < len(s) {
+			if s[i] == escape[0] {
+				l := escapeLen(s[i:])
+				if l < 0 {
+					l = len(s) - i
+				}
+				seq := s[i : i+l]
+				if w >= width && isSGR(seq) {
+					// belongs to the next segment
+					st.update(seq)
+					i += l
+					continue
+				}
+				st.update(seq)
+				open = st.active != ""
+				b.WriteString(seq)
+				i += l
+				continue
+			}
+
+			r, size := utf8.DecodeRuneInString(s[i:])
+			rw := runeWidth(r)
+			if w+rw > width {
+				break
+			}
+			b.WriteString(s[i : i+size])
+			w += rw
+			i += size
+		}
+
+		if open {
+			b.WriteString(resetSequence)
+		}
+		if width > w {
+			b.WriteString(strings.Repeat(" ", width-w))
+		}
+		cols[n] = b.String()
+	}
+
+	return cols
+}
+
+// VisibleLength returns the display width of s in terminal cells, ignoring
+// escape sequences. Wide runes such as CJK ideographs count as two cells and
+// zero width runes such as combining marks are not counted, so the result
+// can be used to align columns.
+func VisibleLength(s string) int {
+	return visibleWidth(s)
+}
+
+// visibleWidth returns the number of terminal cells used to display s,
+// ignoring escape sequences.
+func visibleWidth(s string) int {
+	w := 0
+	for i := 0; i < len(s); {
+		if s[i] == escape[0] {
+			l := escapeLen(s[i:])
+			if l < 0 {
+				break
+			}
+			i += l
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		w += runeWidth(r)
+		i += size
+	}
+
+	return w
+}
+
+// Truncate shortens s to at most width visible cells, ending it with
+// ellipsis when anything is cut. Escape sequences and runes are never split,
+// and a reset is appended if a colour is still open at the cut so that it
+// does not leak into following output. Hyperlinks left open are closed too.
+// Strings that already fit are returned unchanged, and the ellipsis is left
+// out if it is wider than width itself.
+func Truncate(s string, width int, ellipsis string) string {
+	if visibleWidth(s) <= width {
+		return s
+	}
+
+	ew := visibleWidth(ellipsis)
+	if ew > width {
+		ellipsis, ew = "", 0
+	}
+	limit := width - ew
+
+	var b strings.Builder
+	var st sgrState
+	link := false
+	w := 0
+	for i := 0; i < len(s); {
+		if s[i] == escape[0] {
+			l := escapeLen(s[i:])
+			if l < 0 {
+				break
+			}
+			seq := s[i : i+l]
+			i += l
+			if w >= limit {
+				// nothing visible follows, so the sequence has no effect
+				continue
+			}
+			st.update(seq)
+			if strings.HasPrefix(seq, escape+"]8;") {
+				link = !strings.HasPrefix(seq, hyperlinkClose)
+			}
+			b.WriteString(seq)
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		rw := runeWidth(r)
+		if w+rw > limit {
+			break
+		}
+		b.WriteString(s[i : i+size])
+		w += rw
+		i += size
+	}
+
+	b.WriteString(ellipsis)
+	if st.active != "" {
+		b.WriteString(resetSequence)
+	}
+	if link {
+		b.WriteString(hyperlinkClose)
+	}
+
+	return b.String()
+}
+
+// PadRight is like Sprint but pads the result on the right with spaces up to
+// the given visible width, unlike fmt's "%-20s" which counts the bytes of the
+// escape sequences too. The padding is placed outside of the colour. Content
+// wider than width is returned unchanged.
+func (c *Colour) PadRight(width int, a ...interface{}) string {
+	return padRight(c.Sprint(a...), width)
+}
+
+// PadLeft is like PadRight but pads on the left, aligning the content to the
+// right.
+func (c *Colour) PadLeft(width int, a ...interface{}) string {
+	return padLeft(c.Sprint(a...), width)
+}
+
+// PadInside is like PadRight but places the padding inside of the colour, so
+// that a background fills the whole width, for example in the cells of a
+// table. Content wider than width is not truncated, use Truncate for that.
+func (c *Colour) PadInside(width int, a ...interface{}) string {
+	return c.wrap(padRight(fmt.Sprint(a...), width))
+}
+
+// SprintPrefix returns s with only its first n visible runes coloured, for
+// example gutter markers, followed by a reset and the rest of s left plain.
+// Escape sequences in s are not counted. All of s is coloured if it has no
+// more than n runes, and s is returned unchanged if n is not positive.
+func (c *Colour) SprintPrefix(n int, s string) string {
+	s = sanitize(s)
+	if n <= 0 || s == "" {
+		return s
+	}
+
+	i := 0
+	for runes := 0; i < len(s) && runes < n; runes++ {
+		for i < len(s) && s[i] == escape[0] {
+			l := escapeLen(s[i:])
+			if l < 0 {
+				l = len(s) - i
+			}
+			i += l
+		}
+		if i < len(s) {
+			_, size := utf8.DecodeRuneInString(s[i:])
+			i += size
+		}
+	}
+
+	return c.wrap(s[:i]) + s[i:]
+}
+
+// padRight pads s with spaces to the given visible width.
+func padRight(s string, width int) string {
+	if n := width - visibleWidth(s); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+
+	return s
+}
+
+// padLeft pads s with leading spaces to the given visible width.
+func padLeft(s string, width int) string {
+	if n := width - visibleWidth(s); n > 0 {
+		return strings.Repeat(" ", n) + s
+	}
+
+	return s
+}
+
+// Wrap word wraps s to lines of at most width visible cells, breaking at
+// spaces. Escape sequences are never split and do not count towards the
+// width. A line broken while a colour is in effect ends with a reset and the
+// colour is opened again on the next line, so each line renders correctly on
+// its own. Words wider than width are split where the line is full. Existing
+// newlines are kept, and spaces at inserted line breaks are dropped. s is
+// returned unchanged if width is not positive.
+func Wrap(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+
+	w := wrapper{width: width}
+	word := 0 // start of the current word
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == '\n':
+			w.word(s[word:i])
+			w.b.WriteByte('\n')
+			w.col, w.spaces = 0, ""
+			i++
+			word = i
+		case c == ' ' || c == '\t':
+			w.word(s[word:i])
+			start := i
+			for i < len(s) && (s[i] == ' ' || s[i] == '\t') {
+				i++
+			}
+			w.spaces = s[start:i]
+			word = i
+		case c == escape[0]:
+			l := escapeLen(s[i:])
+			if l < 0 {
+				l = len(s) - i
+			}
+			i += l
+		default:
+			_, size := utf8.DecodeRuneInString(s[i:])
+			i += size
+		}
+	}
+	w.word(s[word:])
+
+	return w.b.String()
+}
+
+// wrapper holds the state of Wrap.
+type wrapper struct {
+	b      strings.Builder
+	st     sgrState
+	width  int
+	col    int    // visible width of the current line
+	spaces string // spaces before the next word
+}
+
+// word adds a word, preceded by the pending spaces if it fits on the current
+// line and on a new line otherwise.
+func (w *wrapper) word(s string) {
+	if s == "" {
+		return
+	}
+
+	ww := visibleWidth(s)
+	sw := visibleWidth(w.spaces)
+	if w.col > 0 && w.col+sw+ww > w.width {
+		w.newline()
+	} else {
+		w.b.WriteString(w.spaces)
+		w.col += sw
+	}
+	w.spaces = ""
+
+	if w.col+ww <= w.width {
+		w.b.WriteString(s)
+		w.st.feed(s)
+		w.col += ww
+		return
+	}
+
+	// too long for any line, split it
+	for i := 0; i < len(s); {
+		if s[i] == escape[0] {
+			l := escapeLen(s[i:])
+			if l < 0 {
+				l = len(s) - i
+			}
+			w.b.WriteString(s[i : i+l])
+			w.st.update(s[i : i+l])
+			i += l
+			continue
+		}
+
+		_, size := utf8.DecodeRuneInString(s[i:])
+		rw := visibleWidth(s[i : i+size])
+		if w.col > 0 && w.col+rw > w.width {
+			w.newline()
+		}
+		w.b.WriteString(s[i : i+size])
+		w.col += rw
+		i += size
+	}
+}
+
+// newline breaks the line, closing and reopening the style in effect.
+func (w *wrapper) newline() {
+	if w.st.active != "" {
+		w.b.WriteString(resetSequence)
+	}
+	w.b.WriteByte('\n')
+	w.b.WriteString(w.st.active)
+	w.col, w.spaces = 0, ""
+}
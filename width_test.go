@@ -0,0 +1,203 @@
+package colour
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRuneWidth(t *testing.T) {
+	tests := []struct {
+		r    rune
+		want int
+	}{
+		{'a', 1},
+		{'é', 1},
+		{'́', 0}, // combining acute accent
+		{'‍', 0}, // zero width joiner
+		{'世', 2},
+		{'ｱ', 1}, // halfwidth katakana
+		{'Ａ', 2}, // fullwidth latin
+		{'😀', 2},
+		{'\t', 0},
+	}
+
+	for _, tt := range tests {
+		if got := runeWidth(tt.r); got != tt.want {
+			t.Errorf("%q want: %d, got: %d", tt.r, tt.want, got)
+		}
+	}
+}
+
+func TestVisibleLength(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"", 0},
+		{"abc", 3},
+		{"\x1b[31mred\x1b[0m", 3},
+		{"\x1b[38;2;1;2;3mgrüße\x1b[0m", 5},
+		{"e\u0301", 1},
+		{"\x1b[1m世界\x1b[0m!", 5},
+		{"\x1b]8;;http://example.com\x1b\\link\x1b]8;;\x1b\\", 4},
+	}
+
+	for i, tt := range tests {
+		if got := VisibleLength(tt.s); got != tt.want {
+			t.Errorf("[%d] want: %d, got: %d", i, tt.want, got)
+		}
+	}
+}
+
+func TestColumns(t *testing.T) {
+	tests := []struct {
+		s      string
+		widths []int
+		want   []string
+	}{
+		{"abcdef", []int{2, 2, 2}, []string{"ab", "cd", "ef"}},
+		{"abc", []int{2, 3}, []string{"ab", "c  "}},
+		{"abcdef", []int{2}, []string{"ab"}},
+		{
+			"\x1b[31mabcd\x1b[0mef",
+			[]int{2, 3, 2},
+			[]string{"\x1b[31mab\x1b[0m", "\x1b[31mcd\x1b[0me", "f "},
+		},
+		{
+			"ab\x1b[1m\x1b[32mcd\x1b[0m",
+			[]int{2, 2},
+			[]string{"ab", "\x1b[1m\x1b[32mcd\x1b[0m"},
+		},
+		{"世界", []int{3, 2}, []string{"世 ", "界"}},
+		{"ée", []int{1, 1}, []string{"é", "e"}},
+	}
+
+	for i, tt := range tests {
+		got := Columns(tt.s, tt.widths)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+	}
+}
+
+func TestPad(t *testing.T) {
+	NoColour = false
+	red := New(FgRed)
+
+	tests := []struct {
+		got, want string
+	}{
+		{red.PadRight(5, "hi"), "\x1b[31mhi\x1b[0m   "},
+		{red.PadLeft(5, "hi"), "   \x1b[31mhi\x1b[0m"},
+		{red.PadRight(4, "世界"), "\x1b[31m世界\x1b[0m"},
+		{red.PadRight(3, "too long"), "\x1b[31mtoo long\x1b[0m"},
+		{red.PadLeft(3, "too long"), "\x1b[31mtoo long\x1b[0m"},
+		{red.PadRight(4, 1, 2), "\x1b[31m1 2\x1b[0m "},
+		{red.PadInside(5, "hi"), "\x1b[31mhi   \x1b[0m"},
+		{red.PadInside(4, "世界"), "\x1b[31m世界\x1b[0m"},
+		{red.PadInside(3, "too long"), "\x1b[31mtoo long\x1b[0m"},
+		{red.PadInside(4, "\x1b[1mhi\x1b[22m"), "\x1b[31m\x1b[1mhi\x1b[22m  \x1b[0m"},
+	}
+
+	for i, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, tt.got)
+		}
+	}
+
+	red.DisableColour()
+	if got, want := red.PadLeft(4, "x"), "   x"; got != want {
+		t.Errorf("want: %q, got: %q", want, got)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s        string
+		width    int
+		ellipsis string
+		want     string
+	}{
+		{"short", 10, "…", "short"},
+		{"exactly", 7, "…", "exactly"},
+		{"truncated", 6, "…", "trunc…"},
+		{"truncated", 6, "", "trunca"},
+		{"truncated", 6, "...", "tru..."},
+		{"truncated", 2, "...", "tr"},
+		// cut inside a coloured region
+		{"\x1b[31mtruncated\x1b[0m", 6, "…", "\x1b[31mtrunc…\x1b[0m"},
+		{"\x1b[1m\x1b[31mtruncated\x1b[0m", 4, "", "\x1b[1m\x1b[31mtrun\x1b[0m"},
+		// cut outside of it
+		{"\x1b[31mred\x1b[0m plain", 6, "…", "\x1b[31mred\x1b[0m p…"},
+		{"\x1b[31mred\x1b[0m\x1b[32mgreen\x1b[0m", 3, "", "\x1b[31mred\x1b[0m"},
+		// runes are never split
+		{"世界世界", 5, "…", "世界…"},
+		{"世界世界", 4, "", "世界"},
+		{"ééé", 2, "", "éé"},
+		{"\x1b]8;;http://x\x1b\\link text\x1b]8;;\x1b\\", 4, "", "\x1b]8;;http://x\x1b\\link\x1b]8;;\x1b\\"},
+	}
+
+	for i, tt := range tests {
+		if got := Truncate(tt.s, tt.width, tt.ellipsis); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"", 10, ""},
+		{"short", 10, "short"},
+		{"short", 0, "short"},
+		{"the quick brown fox", 10, "the quick\nbrown fox"},
+		{"the quick brown fox", 9, "the quick\nbrown fox"},
+		{"the  quick", 5, "the\nquick"},
+		{"a b c d", 3, "a b\nc d"},
+		{"  indented text", 10, "  indented\ntext"},
+		{"one\ntwo three", 5, "one\ntwo\nthree"},
+		{"abcdefghij", 4, "abcd\nefgh\nij"},
+		{"ab abcdefgh", 4, "ab\nabcd\nefgh"},
+		{"世界 世界世界", 4, "世界\n世界\n世界"},
+		// colours are closed and reopened at inserted breaks
+		{"\x1b[31mhello world\x1b[0m", 5, "\x1b[31mhello\x1b[0m\n\x1b[31mworld\x1b[0m"},
+		{"\x1b[1mbold\x1b[0m plain text", 10, "\x1b[1mbold\x1b[0m plain\ntext"},
+		{"\x1b[32mabcdef\x1b[0m", 3, "\x1b[32mabc\x1b[0m\n\x1b[32mdef\x1b[0m"},
+		{"\x1b[31mred\x1b[0m\nnext", 4, "\x1b[31mred\x1b[0m\nnext"},
+	}
+
+	for i, tt := range tests {
+		if got := Wrap(tt.s, tt.width); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+	}
+}
+
+func TestSprintPrefix(t *testing.T) {
+	NoColour = false
+
+	red := New(FgRed)
+	tests := []struct {
+		n    int
+		s    string
+		want string
+	}{
+		{1, "> line", "\x1b[31m>\x1b[0m line"},
+		{2, "grüße", "\x1b[31mgr\x1b[0müße"},
+		{3, "grüße", "\x1b[31mgrü\x1b[0mße"},
+		{10, "short", "\x1b[31mshort\x1b[0m"},
+		{0, "none", "none"},
+		{-1, "none", "none"},
+		{3, "", ""},
+		{2, "a\x1b[1mbc", "\x1b[31ma\x1b[1mb\x1b[0mc"},
+	}
+
+	for i, tt := range tests {
+		if got := red.SprintPrefix(tt.n, tt.s); got != tt.want {
+			t.Errorf("[%d] want: %q, got: %q", i, tt.want, got)
+		}
+	}
+}